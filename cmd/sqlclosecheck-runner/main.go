@@ -0,0 +1,12 @@
+// Command sqlclosecheck-runner runs the analyzer standalone, owning the output
+// to emit JSON findings with stable fingerprints, suppressions and statistics.
+package main
+
+import (
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"github.com/ryanrolds/sqlclosecheck/pkg/runner"
+)
+
+func main() {
+	runner.Main(analyzer.NewAnalyzer())
+}
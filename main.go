@@ -1,10 +1,21 @@
 package main
 
 import (
+	"log"
+	"os"
+
 	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
 	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
 func main() {
-	singlechecker.Main(analyzer.NewAnalyzer())
+	a := analyzer.NewAnalyzer()
+	if analyzer.ListChecksRequested(a, os.Args[1:]) {
+		if err := analyzer.WriteChecks(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	singlechecker.Main(a)
 }
@@ -11,12 +11,8 @@ import (
 // Deprecated, this will be removed in v1.0.0.
 func NewAnalyzer() *analysis.Analyzer {
 	flags := flag.NewFlagSet("analyzer", flag.ExitOnError)
-	return newAnalyzer(run, flags)
-}
-
-func run(pass *analysis.Pass) (interface{}, error) {
-	opinionatedAnalyzer := &deferOnlyAnalyzer{}
-	return opinionatedAnalyzer.Run(pass)
+	opinionatedAnalyzer := newDeferOnlyAnalyzer(flags)
+	return newAnalyzer(opinionatedAnalyzer.Run, flags)
 }
 
 // newAnalyzer returns a new analyzer with the given run function, should be used by all analyzers.
@@ -24,10 +20,16 @@ func newAnalyzer(
 	r func(pass *analysis.Pass) (interface{}, error),
 	flags *flag.FlagSet,
 ) *analysis.Analyzer {
+	// Read by the commands, which print the checks with WriteChecks and exit
+	flags.Bool("list-checks", false, "print the available checks and exit")
+
 	return &analysis.Analyzer{
-		Name: "sqlclosecheck",
-		Doc:  "Checks that sql.Rows, sql.Stmt, sqlx.NamedStmt, pgx.Query are closed.",
-		Run:  r,
+		Name:       "sqlclosecheck",
+		Doc:        "Checks that sql.Rows, sql.Stmt, sqlx.NamedStmt, pgx.Query are closed.",
+		Run:        r,
+		Flags:      *flags,
+		ResultType: resultType,
+		FactTypes:  []analysis.Fact{new(closesParamsFact)},
 		Requires: []*analysis.Analyzer{
 			buildssa.Analyzer,
 		},
@@ -0,0 +1,79 @@
+package analyzer_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// writeQueries writes a module with a package of n functions, each querying,
+// iterating and closing rows, half of them leaking
+func writeQueries(tb testing.TB, n int) string {
+	tb.Helper()
+
+	var src strings.Builder
+	src.WriteString("package queries\n\nimport (\n\t\"context\"\n\t\"database/sql\"\n)\n")
+	for i := 0; i < n; i++ {
+		want, closeRows := "", "\n\tdefer rows.Close()\n"
+		if i%2 == 1 {
+			want, closeRows = ` // want "database/sql.Rows was not closed"`, "\n"
+		}
+
+		fmt.Fprintf(&src, `
+func query%d(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")%s
+	if err != nil {
+		return nil, err
+	}%s
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+`, i, want, closeRows)
+	}
+
+	dir := tb.TempDir()
+	pkgDir := filepath.Join(dir, "queries")
+	if err := os.MkdirAll(pkgDir, 0o755); err != nil {
+		tb.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/bench\n\ngo 1.20\n"), 0o600); err != nil {
+		tb.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(pkgDir, "queries.go"), []byte(src.String()), 0o600); err != nil {
+		tb.Fatal(err)
+	}
+
+	return dir
+}
+
+func BenchmarkDeferOnlyAnalyzer(b *testing.B) {
+	dir := writeQueries(b, 200)
+
+	comparisons := 0
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		results := analysistest.Run(b, dir, analyzer.NewDeferOnlyAnalyzer(), "example.com/bench/queries")
+		for _, r := range results {
+			if result, ok := r.Result.(*analyzer.Result); ok {
+				comparisons += result.Stats.TypeComparisons
+			}
+		}
+	}
+
+	b.ReportMetric(float64(comparisons)/float64(b.N), "comparisons/op")
+}
@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lineRange is an inclusive range of lines
+type lineRange struct {
+	start, end int
+}
+
+// changedLines restricts reporting to the changed lines of files. It is set
+// either to a spec of file:line ranges, e.g. a.go:10-20,b.go:7, or to the path
+// of a unified diff whose added lines are the changed ones.
+type changedLines map[string][]lineRange
+
+func (c changedLines) String() string {
+	specs := []string{}
+	for file, ranges := range c {
+		for _, r := range ranges {
+			specs = append(specs, fmt.Sprintf("%s:%d-%d", file, r.start, r.end))
+		}
+	}
+	sort.Strings(specs)
+
+	return strings.Join(specs, ",")
+}
+
+func (c changedLines) Set(value string) error {
+	if info, err := os.Stat(value); err == nil && !info.IsDir() {
+		return c.setDiff(value)
+	}
+
+	for _, spec := range strings.Split(value, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		idx := strings.LastIndex(spec, ":")
+		if idx <= 0 {
+			return fmt.Errorf("invalid changed lines %q, expected file:start-end", spec)
+		}
+
+		r, err := parseRange(spec[idx+1:])
+		if err != nil {
+			return fmt.Errorf("invalid changed lines %q: %w", spec, err)
+		}
+
+		file := filepath.ToSlash(spec[:idx])
+		c[file] = append(c[file], r)
+	}
+
+	return nil
+}
+
+func parseRange(s string) (lineRange, error) {
+	startStr, endStr, found := strings.Cut(s, "-")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return lineRange{}, err
+	}
+
+	end := start
+	if found {
+		if end, err = strconv.Atoi(endStr); err != nil {
+			return lineRange{}, err
+		}
+	}
+
+	if start <= 0 || end < start {
+		return lineRange{}, fmt.Errorf("invalid range %q", s)
+	}
+
+	return lineRange{start: start, end: end}, nil
+}
+
+// setDiff adds the lines added by the hunks of the unified diff at path. The
+// context lines of the hunks aren't changed.
+func (c changedLines) setDiff(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	file := ""
+	// The next line of the new file, and the lines left in the hunk of each side
+	line, oldLeft, newLeft := 0, 0, 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				c.add(file, line)
+				line++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				oldLeft--
+			case strings.HasPrefix(text, "\\"):
+				// \ No newline at end of file
+			default:
+				line++
+				oldLeft--
+				newLeft--
+			}
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			fields := strings.Fields(text[4:])
+			if len(fields) == 0 {
+				return fmt.Errorf("invalid file header %q", text)
+			}
+
+			file = strings.TrimPrefix(fields[0], "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case strings.HasPrefix(text, "@@ "):
+			// @@ -l,s +l,s @@
+			fields := strings.Fields(text)
+			if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+				return fmt.Errorf("invalid hunk header %q", text)
+			}
+
+			if _, oldLeft, err = parseHunkRange(fields[1][1:]); err != nil {
+				return fmt.Errorf("invalid hunk header %q: %w", text, err)
+			}
+
+			if line, newLeft, err = parseHunkRange(fields[2][1:]); err != nil {
+				return fmt.Errorf("invalid hunk header %q: %w", text, err)
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// parseHunkRange parses the l,s range of one side of a hunk header, s defaults to 1
+func parseHunkRange(s string) (int, int, error) {
+	startStr, countStr, found := strings.Cut(s, ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	count := 1
+	if found {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return start, count, nil
+}
+
+// add adds line of file, extending the range ending right before it
+func (c changedLines) add(file string, line int) {
+	if file == "" {
+		return
+	}
+
+	ranges := c[file]
+	if n := len(ranges); n > 0 && ranges[n-1].end == line-1 {
+		ranges[n-1].end = line
+		return
+	}
+
+	c[file] = append(ranges, lineRange{start: line, end: line})
+}
+
+// contains reports whether line of filename is changed. Without any changed
+// lines every line is.
+func (c changedLines) contains(filename string, line int) bool {
+	if len(c) == 0 {
+		return true
+	}
+
+	filename = filepath.ToSlash(filename)
+	for file, ranges := range c {
+		if filename != file && !strings.HasSuffix(filename, "/"+file) {
+			continue
+		}
+
+		for _, r := range ranges {
+			if r.start <= line && line <= r.end {
+				return true
+			}
+		}
+	}
+
+	return false
+}
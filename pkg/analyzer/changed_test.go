@@ -0,0 +1,82 @@
+package analyzer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+const changedDiff = `diff --git a/changed/changed.go b/changed/changed.go
+--- a/changed/changed.go
++++ b/changed/changed.go
+@@ -12,0 +13,4 @@ func unchangedLeak(ctx context.Context, db *sql.DB) {
++func changedLeak(ctx context.Context, db *sql.DB) {
++	rows, _ := db.QueryContext(ctx, "SELECT username FROM users")
++	_ = rows
++}
+`
+
+// changedContextDiff adds changedLeak after unchangedLeak, whose leak is on a
+// context line of the hunk
+const changedContextDiff = `diff --git a/changed/changed.go b/changed/changed.go
+--- a/changed/changed.go
++++ b/changed/changed.go
+@@ -8,4 +8,9 @@
+ func unchangedLeak(ctx context.Context, db *sql.DB) {
+ 	rows, _ := db.QueryContext(ctx, "SELECT username FROM users")
+ 	_ = rows
+ }
++
++func changedLeak(ctx context.Context, db *sql.DB) {
++	rows, _ := db.QueryContext(ctx, "SELECT username FROM users")
++	_ = rows
++}
+`
+
+func writeDiff(t *testing.T, content string) string {
+	t.Helper()
+
+	diff := filepath.Join(t.TempDir(), "changes.diff")
+	if err := os.WriteFile(diff, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return diff
+}
+
+func TestChangedLines(t *testing.T) {
+	t.Parallel()
+
+	for name, value := range map[string]string{
+		"spec":         "changed/changed.go:13-16,other.go:1",
+		"diff":         writeDiff(t, changedDiff),
+		"context diff": writeDiff(t, changedContextDiff),
+	} {
+		value := value
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testdata := analysistest.TestData()
+			checker := analyzer.NewDeferOnlyAnalyzer()
+			if err := checker.Flags.Set("changed-lines", value); err != nil {
+				t.Fatal(err)
+			}
+
+			analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/changed")
+		})
+	}
+}
+
+func TestChangedLinesInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"missing.go", "missing.go:a-b", "missing.go:5-1", writeDiff(t, "+++ \n")} {
+		if err := analyzer.NewDeferOnlyAnalyzer().Flags.Set("changed-lines", value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// check describes a diagnostic the analyzer can emit. Optional checks are
+// toggled by a boolean flag, checks without a flag always run.
+type check struct {
+	name    string
+	flag    string
+	enabled bool
+	doc     string
+	// category is set on the diagnostics of the check, CategoryLeak or CategoryStyle
+	category string
+}
+
+// Categories of the diagnostics, so downstream tools can filter them, e.g.
+// fail on leaks and only warn on style
+const (
+	// CategoryLeak is a resource left open, e.g. Rows that aren't closed
+	CategoryLeak = "leak"
+	// CategoryStyle is a resource closed in a way that should be improved, e.g. a Close that isn't deferred
+	CategoryStyle = "style"
+)
+
+const (
+	checkUnclosed     = "unclosed"
+	checkDefer        = "defer"
+	checkUnfinishedTx = "unfinished-tx"
+	checkDoubleClose  = "double-close"
+	checkUnusedRows   = "unused-rows"
+	checkUnusedStmt   = "unused-stmt"
+	checkRowsErr      = "rows-err"
+	checkFieldClose   = "field-close"
+	checkCloseErr     = "close-err-in-writes"
+	checkCloseError   = "close-error"
+	checkDepthLimit   = "recursion-limit"
+)
+
+// checks is the registry consulted both when registering flags and when
+// listing the available checks.
+var checks = []check{
+	{name: checkUnclosed, enabled: true, doc: "Rows/Stmt/NamedStmt must be closed", category: CategoryLeak},
+	{name: checkDefer, flag: "require-defer", enabled: true, doc: "Close must be deferred", category: CategoryStyle},
+	{name: checkUnfinishedTx, enabled: true, doc: "Tx must be committed or rolled back", category: CategoryLeak},
+	{name: checkDoubleClose, flag: "check-double-close", doc: "Rows/Stmt must not be closed more than once", category: CategoryStyle},
+	{name: checkFieldClose, flag: "check-field-close", doc: "Rows/Stmt stored in a struct field must be closed by a function of the package", category: CategoryLeak},
+	{name: checkCloseErr, flag: "check-close-err-in-writes", doc: "Close errors of Stmt used for writes must not be dropped by a deferred Close", category: CategoryStyle},
+	{name: checkCloseError, flag: "check-close-error", doc: "Errors returned by a Close that isn't deferred must not be ignored", category: CategoryStyle},
+	{name: checkUnusedRows, flag: "check-unused-rows", doc: "Rows that are never iterated nor closed hint that Exec was intended", category: CategoryLeak},
+	{name: checkUnusedStmt, flag: "check-unused-stmt", doc: "Prepared Stmt that are never executed are dead code", category: CategoryStyle},
+	{name: checkRowsErr, flag: "check-rows-err", doc: "The iteration of database/sql Rows must be followed by a call of Err", category: CategoryStyle},
+	{name: checkDepthLimit, flag: "warn-recursion-limit", doc: "Note targets whose follow-through stopped at -max-depth and are assumed closed", category: CategoryStyle},
+}
+
+// enabledChecks holds the state of every check after the flags are parsed
+type enabledChecks map[string]*bool
+
+// registerChecks registers a flag for each optional check
+func registerChecks(flags *flag.FlagSet) enabledChecks {
+	enabled := enabledChecks{}
+	for _, c := range checks {
+		c := c
+		if c.flag == "" {
+			enabled[c.name] = &c.enabled
+			continue
+		}
+
+		enabled[c.name] = flags.Bool(c.flag, c.enabled, c.doc)
+	}
+
+	return enabled
+}
+
+// checkNames returns the names of the registered checks
+func checkNames() []string {
+	names := make([]string, 0, len(checks))
+	for _, c := range checks {
+		names = append(names, c.name)
+	}
+
+	return names
+}
+
+// checkCategory returns the category of the diagnostics of the check named name
+func checkCategory(name string) string {
+	for _, c := range checks {
+		if c.name == name {
+			return c.category
+		}
+	}
+
+	return ""
+}
+
+func (e enabledChecks) on(name string) bool {
+	enabled, ok := e[name]
+	if !ok {
+		// Analyzers created without flags run the checks in their default state
+		for _, c := range checks {
+			if c.name == name {
+				return c.enabled
+			}
+		}
+
+		return false
+	}
+
+	return *enabled
+}
+
+// WriteChecks writes the registered checks, their flags, default state,
+// category and description to w, as printed by -list-checks
+func WriteChecks(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tFLAG\tDEFAULT\tCATEGORY\tDESCRIPTION")
+	for _, c := range checks {
+		flagName := "-"
+		if c.flag != "" {
+			flagName = "-" + c.flag
+		}
+
+		state := "off"
+		if c.enabled {
+			state = "on"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", c.name, flagName, state, c.category, c.doc)
+	}
+
+	return tw.Flush()
+}
+
+// ListChecksRequested reports whether args set -list-checks of a, for commands
+// to print the checks before handing args to a driver, e.g. singlechecker. The
+// flags of a are parsed into throwaway values, leaving theirs to the driver.
+func ListChecksRequested(a *analysis.Analyzer, args []string) bool {
+	flags := flag.NewFlagSet(a.Name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	listChecks := false
+	a.Flags.VisitAll(func(f *flag.Flag) {
+		if f.Name == "list-checks" {
+			flags.BoolVar(&listChecks, f.Name, false, f.Usage)
+			return
+		}
+
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			flags.Bool(f.Name, false, f.Usage)
+		} else {
+			flags.String(f.Name, "", f.Usage)
+		}
+	})
+
+	// The flags of the driver are unknown here and stop the parsing, the driver reports them
+	_ = flags.Parse(args)
+
+	return listChecks
+}
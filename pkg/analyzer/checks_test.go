@@ -0,0 +1,140 @@
+package analyzer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestWriteChecks(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := analyzer.WriteChecks(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected a header and at least two checks, got:\n%s", buf.String())
+	}
+
+	for _, name := range []string{"unclosed", "defer", analyzer.CategoryLeak, analyzer.CategoryStyle} {
+		if !strings.Contains(buf.String(), name) {
+			t.Errorf("check %q is missing from the listing:\n%s", name, buf.String())
+		}
+	}
+}
+
+func TestListChecksFlagRegistered(t *testing.T) {
+	t.Parallel()
+
+	checkers := map[string]*analysis.Analyzer{
+		"NewAnalyzer":             analyzer.NewAnalyzer(),
+		"NewDeferOnlyAnalyzer":    analyzer.NewDeferOnlyAnalyzer(),
+		"NewDeferAnalyzer":        analyzer.NewDeferAnalyzer(),
+		"NewAnalyzerWithOptions":  analyzer.NewAnalyzerWithOptions(analyzer.Options{}),
+		"NewClosedAnalyzer":       analyzer.NewClosedAnalyzer(),
+		"NewConfigurableAnalyzer": analyzer.NewConfigurableAnalyzer(analyzer.ConfigurableAnalyzerDeferOnly),
+	}
+
+	for name, checker := range checkers {
+		if checker.Flags.Lookup("list-checks") == nil {
+			t.Errorf("%s: -list-checks is not registered", name)
+		}
+	}
+}
+
+func TestCategory(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, analyzer.NewDeferOnlyAnalyzer(), "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/category")
+	if len(results) != 1 {
+		t.Fatalf("expected one result, got %d", len(results))
+	}
+
+	result, ok := results[0].Result.(*analyzer.Result)
+	if !ok {
+		t.Fatalf("unexpected result %T", results[0].Result)
+	}
+
+	expected := map[string]string{
+		"unclosed": analyzer.CategoryLeak,
+		"defer":    analyzer.CategoryStyle,
+	}
+	if len(result.Diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %v", len(expected), result.Diagnostics)
+	}
+
+	for _, d := range result.Diagnostics {
+		if d.Category != expected[d.Check] {
+			t.Errorf("expected %s to have category %q, got %q", d.Check, expected[d.Check], d.Category)
+		}
+	}
+}
+
+func TestOnlyCategory(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("only-category", "defer"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/onlycategory")
+}
+
+func TestOnlyCategoryInvalid(t *testing.T) {
+	t.Parallel()
+
+	if err := analyzer.NewDeferOnlyAnalyzer().Flags.Set("only-category", "no-such-check"); err == nil {
+		t.Error("expected an unknown check to be rejected")
+	}
+}
+
+func TestRequireDeferOff(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("require-defer", "false"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/requiredefer")
+}
+
+func TestDeferAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferAnalyzer()
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/onlycategory")
+}
+
+func TestListChecksRequested(t *testing.T) {
+	t.Parallel()
+
+	for args, want := range map[string]bool{
+		"-list-checks":                            true,
+		"--list-checks=true":                      true,
+		"-list-checks=1":                          true,
+		"-json -list-checks ./...":                false,
+		"-list-checks=false ./...":                false,
+		"./... -list-checks":                      false,
+		"-severity list-checks ./...":             false,
+		"-only-category list-checks -list-checks": true,
+	} {
+		a := analyzer.NewAnalyzer()
+		if got := analyzer.ListChecksRequested(a, strings.Fields(args)); got != want {
+			t.Errorf("ListChecksRequested(%q) = %t, want %t", args, got, want)
+		}
+	}
+}
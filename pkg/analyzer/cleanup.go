@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"golang.org/x/tools/go/ssa"
+)
+
+// defaultCleanupFuncs register the func passed to them to run at the end of a test
+var defaultCleanupFuncs = []string{
+	"(*testing.common).Cleanup",
+	"(*testing.T).Cleanup",
+	"(*testing.B).Cleanup",
+	"(*testing.F).Cleanup",
+}
+
+// invokingFuncs call the func passed to them, e.g. once or in a goroutine of the group
+var invokingFuncs = []string{
+	"(*sync.Once).Do",
+	"(*golang.org/x/sync/errgroup.Group).Go",
+}
+
+func (a *deferOnlyAnalyzer) isCleanupFunc(fn *ssa.Function) bool {
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+
+	name := fn.String()
+	for _, cleanup := range defaultCleanupFuncs {
+		if name == cleanup {
+			return true
+		}
+	}
+
+	for _, invoking := range invokingFuncs {
+		if name == invoking {
+			return true
+		}
+	}
+
+	return a.cleanupFuncs.contains(name)
+}
+
+// closureInvoked reports whether the function value v may be invoked: it is
+// called, deferred, kept in a way that isn't followed, passed to a function of
+// another package or passed to a function that eventually invokes it
+func (a *deferOnlyAnalyzer) closureInvoked(v ssa.Value) bool {
+	for _, ref := range *v.Referrers() {
+		call, ok := callCommon(ref)
+		if !ok || call.Value == v {
+			return true
+		}
+
+		callee := call.StaticCallee()
+		if callee == nil || len(funcBody(callee).Blocks) == 0 {
+			return true
+		}
+
+		if a.invokesArg(call, v, map[*ssa.Function]bool{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// invokesArg reports whether the function value v passed to call is invoked by
+// the callee. A cleanup func registers it to run later, a function of the
+// package is followed through to the calls of the parameter.
+func (a *deferOnlyAnalyzer) invokesArg(call *ssa.CallCommon, v ssa.Value, visited map[*ssa.Function]bool) bool {
+	callee := call.StaticCallee()
+	if callee == nil {
+		return false
+	}
+
+	if a.isCleanupFunc(callee) {
+		return true
+	}
+
+	body := funcBody(callee)
+	if len(body.Params) != len(call.Args) {
+		return false
+	}
+
+	if visited[body] {
+		return false
+	}
+	visited[body] = true
+
+	for i, arg := range call.Args {
+		if arg != v {
+			continue
+		}
+
+		param := body.Params[i]
+		for _, ref := range *param.Referrers() {
+			pCall, ok := callCommon(ref)
+			if !ok {
+				// Wrapped into a closure it is invoked with the closure
+				if _, ok := ref.(*ssa.MakeClosure); ok {
+					return true
+				}
+
+				continue
+			}
+
+			if pCall.Value == param || a.invokesArg(pCall, param, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// callCommon returns the call common of a call, defer or go instruction
+func callCommon(instr ssa.Instruction) (*ssa.CallCommon, bool) {
+	switch instr := instr.(type) {
+	case *ssa.Call:
+		return &instr.Call, true
+	case *ssa.Defer:
+		return &instr.Call, true
+	case *ssa.Go:
+		return &instr.Call, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ssa"
+)
+
+// writeMethods are the Stmt methods running statements that write
+var writeMethods = []string{"Exec", "ExecContext"}
+
+// checkCloseErrInWrites reports a deferred Close dropping the error of a Stmt
+// used for writes, in a function that returns an error
+func (a *deferOnlyAnalyzer) checkCloseErrInWrites(rep *reporter, v ssa.Value) {
+	if !strings.HasSuffix(targetTypeName(v.Type()), ":"+stmtName) || !returnsError(v.Parent()) {
+		return
+	}
+
+	writes := false
+	for _, ref := range *v.Referrers() {
+		for _, method := range writeMethods {
+			if _, ok := methodCall(ref, v, method); ok {
+				writes = true
+			}
+		}
+	}
+
+	if !writes {
+		return
+	}
+
+	for _, ref := range *v.Referrers() {
+		if _, ok := ref.(*ssa.Defer); !ok {
+			continue
+		}
+
+		if _, ok := closeCall(ref, v); ok {
+			rep.report(checkCloseErr, v.Type(), analysis.Diagnostic{
+				Pos:            ref.Pos(),
+				Message:        "Close error of a Stmt used for writes is dropped",
+				SuggestedFixes: joinCloseErrFix(rep.pass, ref.Pos(), v.Parent()),
+			})
+		}
+	}
+}
+
+// returnsError reports whether the last result of fn is an error
+func returnsError(fn *ssa.Function) bool {
+	results := fn.Signature.Results()
+	if results.Len() == 0 {
+		return false
+	}
+
+	return types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type())
+}
+
+// joinCloseErrFix returns a fix joining the error of the deferred close at pos
+// into the named error result of fn
+func joinCloseErrFix(pass *analysis.Pass, pos token.Pos, fn *ssa.Function) []analysis.SuggestedFix {
+	results := fn.Signature.Results()
+	errName := results.At(results.Len() - 1).Name()
+	if errName == "" || errName == "_" {
+		return nil
+	}
+
+	file := fileOf(pass, pos)
+	if file == nil {
+		return nil
+	}
+
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	var deferStmt *ast.DeferStmt
+	for _, node := range path {
+		if stmt, ok := node.(*ast.DeferStmt); ok {
+			deferStmt = stmt
+			break
+		}
+	}
+
+	if deferStmt == nil {
+		return nil
+	}
+
+	sel, ok := deferStmt.Call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	edits := []analysis.TextEdit{{
+		Pos: deferStmt.Pos(),
+		End: deferStmt.End(),
+		NewText: []byte("defer func() { " + errName + " = errors.Join(" + errName + ", " +
+			types.ExprString(sel.X) + ".Close()) }()"),
+	}}
+
+	if edit, ok := addImportEdit(file, "errors"); ok {
+		edits = append(edits, edit)
+	}
+
+	return []analysis.SuggestedFix{{
+		Message:   "Join the Close error into " + errName,
+		TextEdits: edits,
+	}}
+}
+
+// addImportEdit returns an edit importing path in file, unless it is already
+// imported. The import is placed in order in the first import declaration.
+func addImportEdit(file *ast.File, path string) (analysis.TextEdit, bool) {
+	for _, spec := range file.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err == nil && p == path {
+			return analysis.TextEdit{}, false
+		}
+	}
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT || !genDecl.Lparen.IsValid() {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			spec := spec.(*ast.ImportSpec)
+			if p, err := strconv.Unquote(spec.Path.Value); err == nil && p > path {
+				return analysis.TextEdit{Pos: spec.Pos(), End: spec.Pos(), NewText: []byte(strconv.Quote(path) + "\n\t")}, true
+			}
+		}
+
+		return analysis.TextEdit{Pos: genDecl.Rparen, End: genDecl.Rparen, NewText: []byte("\t" + strconv.Quote(path) + "\n")}, true
+	}
+
+	return analysis.TextEdit{Pos: file.Name.End(), End: file.Name.End(), NewText: []byte("\n\nimport " + strconv.Quote(path))}, true
+}
@@ -0,0 +1,35 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestCloseErrInWrites(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("check-close-err-in-writes", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, testdata, checker,
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/closeerr",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/closeerr/imports",
+	)
+}
+
+func TestCloseError(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("check-close-error", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/closeerror")
+}
@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ssa"
+)
+
+// checkCloseError reports a Close of v, not deferred, whose error is dropped
+// by calling it as a statement. An explicit `_ = rows.Close()` is deliberate
+// and a deferred Close legitimately drops its error.
+func (a *deferOnlyAnalyzer) checkCloseError(rep *reporter, v ssa.Value) {
+	for _, ref := range *v.Referrers() {
+		call, ok := ref.(*ssa.Call)
+		if !ok {
+			continue
+		}
+
+		if _, ok := closeCall(call, v); !ok || call.Call.Signature().Results().Len() == 0 {
+			continue
+		}
+
+		if len(*call.Referrers()) == 0 && isExprStmt(rep.pass, call.Pos()) {
+			rep.reportf(checkCloseError, v.Type(), call.Pos(), "error returned by Close is not checked")
+		}
+	}
+}
+
+// isExprStmt reports whether the call at pos is a statement of its own
+func isExprStmt(pass *analysis.Pass, pos token.Pos) bool {
+	file := fileOf(pass, pos)
+	if file == nil {
+		return false
+	}
+
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	for i, node := range path {
+		if _, ok := node.(*ast.CallExpr); !ok {
+			continue
+		}
+
+		if i+1 >= len(path) {
+			return false
+		}
+
+		_, ok := path[i+1].(*ast.ExprStmt)
+		return ok
+	}
+
+	return false
+}
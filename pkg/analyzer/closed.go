@@ -21,5 +21,5 @@ func (a *closedAnalyzer) Run(pass *analysis.Pass) (interface{}, error) {
 	// 	return nil, nil
 	// }
 
-	return nil, nil
+	return &Result{}, nil
 }
@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// closesParamsFact is exported for functions that close the targets passed
+// as the parameters at Params, so helpers in other packages can be followed
+// through like the ones in the package being analyzed
+type closesParamsFact struct {
+	Params []int
+}
+
+func (*closesParamsFact) AFact() {}
+
+func (f *closesParamsFact) String() string {
+	return fmt.Sprintf("closesParams(%v)", f.Params)
+}
+
+// withImportedClosers returns a copy of the analyzer knowing the closing
+// functions of the packages imported by the pass
+func (a *deferOnlyAnalyzer) withImportedClosers(pass *analysis.Pass) *deferOnlyAnalyzer {
+	withClosers := *a
+	withClosers.closers = map[types.Object][]int{}
+	for _, fact := range pass.AllObjectFacts() {
+		if f, ok := fact.Fact.(*closesParamsFact); ok {
+			withClosers.closers[fact.Object] = f.Params
+		}
+	}
+
+	return &withClosers
+}
+
+// exportClosers exports a fact for each function of the package closing the
+// targets passed to it
+func (a *deferOnlyAnalyzer) exportClosers(pass *analysis.Pass, funcs []*ssa.Function, targetTypes []any) {
+	for _, f := range funcs {
+		obj := f.Object()
+		if obj == nil || len(f.Blocks) == 0 {
+			continue
+		}
+
+		params := []int{}
+		for i, param := range f.Params {
+			if a.isTarget(param.Type()) && closesParam(param) {
+				params = append(params, i)
+			}
+		}
+
+		if len(params) > 0 {
+			pass.ExportObjectFact(obj, &closesParamsFact{Params: params})
+		}
+	}
+}
+
+// closesParam reports whether param is closed by a call or defer of its Close.
+// Returning or wrapping the param hands it over and doesn't count.
+func closesParam(param *ssa.Parameter) bool {
+	for _, ref := range *param.Referrers() {
+		if _, ok := closeCall(ref, param); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// closesArgs reports whether the imported fn closes one of the targets in args
+func (a *deferOnlyAnalyzer) closesArgs(fn *ssa.Function, args []ssa.Value, targetTypes []any) bool {
+	obj := fn.Object()
+	if obj == nil {
+		return false
+	}
+
+	for _, i := range a.closers[obj] {
+		if i < len(args) && a.isTarget(args[i].Type()) {
+			return true
+		}
+	}
+
+	return false
+}
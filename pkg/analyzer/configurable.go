@@ -16,6 +16,8 @@ const (
 
 type ConifgurableAnalyzer struct {
 	Mode string
+
+	deferOnly *deferOnlyAnalyzer
 }
 
 func NewConfigurableAnalyzer(mode ConfigurableModeType) *analysis.Analyzer {
@@ -23,14 +25,14 @@ func NewConfigurableAnalyzer(mode ConfigurableModeType) *analysis.Analyzer {
 	flags := flag.NewFlagSet("cfgAnalyzer", flag.ExitOnError)
 	flags.StringVar(&cfgAnalyzer.Mode, "mode", string(mode),
 		"Mode to run the analyzer in. (defer-only, closed)")
+	cfgAnalyzer.deferOnly = newDeferOnlyAnalyzer(flags)
 	return newAnalyzer(cfgAnalyzer.run, flags)
 }
 
 func (c *ConifgurableAnalyzer) run(pass *analysis.Pass) (interface{}, error) {
 	switch c.Mode {
 	case string(ConfigurableAnalyzerDeferOnly):
-		analyzer := &deferOnlyAnalyzer{}
-		return analyzer.Run(pass)
+		return c.deferOnly.Run(pass)
 	case string(ConfigurableAnalyzerClosed):
 		analyzer := &closedAnalyzer{}
 		return analyzer.Run(pass)
@@ -17,6 +17,16 @@ func TestConfigurableAnalyzerDeferOnly(t *testing.T) {
 		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/rows",
 		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/stmt",
 		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/pgx",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/pgxpool",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/pgxv4",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/bun",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/gopg",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlite3",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlx",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/dbr",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/clickhouse",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlboiler/raw",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/mysql",
 	}
 
 	for _, pkg := range packages {
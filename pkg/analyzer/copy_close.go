@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// isCopy reports whether instr explicitly dereferences a pointer to a struct,
+// copying the value it points to. Closing the copy doesn't close the original.
+// Implicit dereferences, e.g. calling a value method on the pointer, have no
+// position and are not copies made by the user.
+func isCopy(instr *ssa.UnOp) bool {
+	if instr.Op != token.MUL || !instr.Pos().IsValid() {
+		return false
+	}
+
+	_, ok := instr.Type().Underlying().(*types.Struct)
+	return ok
+}
+
+// checkCopyClose reports closes of a dereferenced copy of v
+func (a *deferOnlyAnalyzer) checkCopyClose(rep *reporter, v ssa.Value) {
+	for _, ref := range *v.Referrers() {
+		deref, ok := ref.(*ssa.UnOp)
+		if !ok || !isCopy(deref) {
+			continue
+		}
+
+		// The copy is either the receiver itself or, for pointer receivers,
+		// stored in a local whose address is the receiver
+		copies := []ssa.Value{deref}
+		for _, cRef := range *deref.Referrers() {
+			if store, ok := cRef.(*ssa.Store); ok && store.Val == deref {
+				if alloc, ok := store.Addr.(*ssa.Alloc); ok {
+					copies = append(copies, alloc)
+				}
+			}
+		}
+
+		for _, c := range copies {
+			for _, cRef := range *c.Referrers() {
+				if _, ok := closeCall(cRef, c); ok {
+					rep.reportf(checkUnclosed, v.Type(), cRef.Pos(), "Close is called on a copy and does not close the original Rows/Stmt")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package analyzer
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// handlingInstrs returns the instructions that close the target, return it or
+// hand it over, e.g. by storing it in a field or passing it to a function. The
+// values aliasing the target, such as the loads of a local variable holding
+// it, are followed with a worklist. Both the path walk of leakingReturn and the
+// dataflow of -modern stop at them.
+func (a *deferOnlyAnalyzer) handlingInstrs(v ssa.Value, targetTypes []any) map[ssa.Instruction]bool {
+	handling := map[ssa.Instruction]bool{}
+	visited := map[ssa.Value]bool{}
+	worklist := []ssa.Value{v}
+	for len(worklist) > 0 {
+		v := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+		if visited[v] {
+			continue
+		}
+		visited[v] = true
+
+		refs := *v.Referrers()
+		for idx, ref := range refs {
+			if a.unreachable(ref) {
+				continue
+			}
+
+			switch ref := ref.(type) {
+			case *ssa.Phi:
+				worklist = append(worklist, ref)
+			case *ssa.ChangeType:
+				worklist = append(worklist, ref)
+			case *ssa.Store:
+				alloc, ok := ref.Addr.(*ssa.Alloc)
+				if !ok || ref.Val != v {
+					if a.getAction(v, ref, targetTypes) != actionUnhandled {
+						handling[ref] = true
+					}
+					continue
+				}
+
+				// Kept in a local variable, its loads are the target
+				for _, aRef := range *alloc.Referrers() {
+					if load, ok := aRef.(*ssa.UnOp); ok && load.Op == token.MUL {
+						worklist = append(worklist, load)
+					}
+				}
+
+				if a.getAction(v, ref, targetTypes) == actionHandled {
+					handling[ref] = true
+				}
+			default:
+				switch a.getAction(v, ref, targetTypes) {
+				case actionClosed, actionHandled, actionReturned:
+					handling[ref] = true
+				case actionPassed:
+					// Handed over when it isn't used after, like by checkClosed
+					if idx+1 == len(refs) {
+						handling[ref] = true
+					}
+				}
+			}
+		}
+	}
+
+	return handling
+}
+
+// closedOnEveryPath reports whether every path from the creation of the target
+// to a return of the function goes through one of its handling instructions,
+// walking the blocks with a worklist. A deferred close handles the paths it is
+// registered on. Paths where the query failed, or that end in a panic or a
+// call that never returns, are skipped like by leaksOnSomePath.
+func (a *deferOnlyAnalyzer) closedOnEveryPath(target targetValue, targetTypes []any) bool {
+	handling := a.handlingInstrs(*target.value, targetTypes)
+	guards := nilGuards(target)
+
+	type point struct {
+		block, pred *ssa.BasicBlock
+		from        int
+	}
+
+	start := target.instr.Block()
+	from := 0
+	for i, instr := range start.Instrs {
+		if instr == target.instr {
+			from = i + 1
+			break
+		}
+	}
+
+	visited := map[*ssa.BasicBlock]bool{}
+	worklist := []point{{block: start, from: from}}
+	for len(worklist) > 0 {
+		p := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+
+		handled := false
+		for _, instr := range p.block.Instrs[p.from:] {
+			if instr == target.instr {
+				// A loop back to the creation overwrites the target before it is closed
+				if overwritten(target.instr, p.pred) {
+					return false
+				}
+
+				handled = true
+				break
+			}
+
+			if handling[instr] || isNoReturn(instr) {
+				handled = true
+				break
+			}
+		}
+
+		if handled {
+			continue
+		}
+
+		succs := p.block.Succs
+		switch last := p.block.Instrs[len(p.block.Instrs)-1].(type) {
+		case *ssa.Return:
+			return false
+		case *ssa.Panic:
+			continue
+		case *ssa.If:
+			if skip, ok := guards.skippedSucc(last); ok {
+				succs = []*ssa.BasicBlock{p.block.Succs[1-skip]}
+			}
+		}
+
+		for _, succ := range succs {
+			if !visited[succ] {
+				visited[succ] = true
+				worklist = append(worklist, point{block: succ, pred: p.block})
+			}
+		}
+	}
+
+	return true
+}
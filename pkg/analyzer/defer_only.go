@@ -2,7 +2,10 @@ package analyzer
 
 import (
 	"flag"
+	"go/token"
 	"go/types"
+	"log"
+	"strings"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/buildssa"
@@ -13,7 +16,11 @@ const (
 	rowsName      = "Rows"
 	stmtName      = "Stmt"
 	namedStmtName = "NamedStmt"
+	connName      = "Conn"
+	iteratorName  = "Iterator"
 	closeMethod   = "Close"
+	// defaultDepthLimit is far deeper than the follow-through of real code
+	defaultDepthLimit = 100
 )
 
 type action uint8
@@ -22,6 +29,8 @@ const (
 	actionUnhandled action = iota
 	actionHandled
 	actionReturned
+	// actionPassed hands the target to a call that isn't followed, it is
+	// trusted to be closed there when the call is its last use
 	actionPassed
 	actionClosed
 	actionUnvaluedCall
@@ -33,66 +42,357 @@ var (
 	sqlPackages = []string{
 		"database/sql",
 		"github.com/jmoiron/sqlx",
+		"github.com/jackc/pgx/v4",
+		"github.com/jackc/pgx/v4/pgxpool",
 		"github.com/jackc/pgx/v5",
 		"github.com/jackc/pgx/v5/pgxpool",
+		"github.com/uptrace/bun",
+		"github.com/go-pg/pg/v10",
+		"github.com/mattn/go-sqlite3",
+		"github.com/gocraft/dbr/v2",
+		"github.com/ClickHouse/clickhouse-go/v2",
+		// the native interface of clickhouse-go, its Rows is an interface like pgx.Rows
+		"github.com/ClickHouse/clickhouse-go/v2/lib/driver",
+		// the raw queries of sqlboiler, e.g. queries.Raw(...).QueryContext returning *sql.Rows
+		"github.com/volatiletech/sqlboiler/v4/queries",
+		"github.com/go-sql-driver/mysql",
+	}
+
+	// connPackages are the sqlPackages whose Conn holds a connection of the
+	// pool until it is closed, e.g. sql.DB.Conn, sqlx's DB.Connx or go-pg's DB.Conn
+	connPackages = map[string]bool{
+		"database/sql":            true,
+		"github.com/go-pg/pg/v10": true,
+		"github.com/jmoiron/sqlx": true,
+	}
+
+	// driverPackages are the sqlPackages implementing database/sql/driver, all
+	// of whose types with a Close() error are checked. Their Rows and Stmt are
+	// reached by asserting the driver interfaces, e.g. rows.(*sqlite3.SQLiteRows)
+	driverPackages = map[string]bool{
+		"github.com/mattn/go-sqlite3":    true,
+		"github.com/go-sql-driver/mysql": true,
+	}
+
+	// unexportedDriverPackages are the driverPackages whose Rows, Stmt and Conn
+	// are unexported, e.g. mysqlRows, and only reached as the interfaces of
+	// database/sql/driver, which are checked instead
+	unexportedDriverPackages = map[string]bool{
+		"github.com/go-sql-driver/mysql": true,
 	}
 )
 
-type deferOnlyAnalyzer struct{}
+type deferOnlyAnalyzer struct {
+	// packages overrides sqlPackages when set
+	packages []string
+	checks   enabledChecks
+	// extraPackages are checked in addition to sqlPackages
+	extraPackages stringsFlag
+	// packagesOnly are checked instead of sqlPackages
+	packagesOnly stringsFlag
+	// onlyCheck runs this single check instead of the enabled ones when set
+	onlyCheck choiceFlag
+	// ownershipFuncs take over the targets passed to them
+	ownershipFuncs stringsFlag
+	// cleanupFuncs register the funcs passed to them to run later, e.g. on return
+	cleanupFuncs stringsFlag
+	// targetTypeNames are additional fully qualified types to check, e.g. result wrappers
+	targetTypeNames stringsFlag
+	// closeMethods are the method names closing a target in addition to Close, e.g. Release
+	closeMethods   stringsFlag
+	severities     severityFlag
+	returnedPolicy choiceFlag
+	changed        changedLines
+	// includeGenerated reports findings in generated files, which are skipped by default
+	includeGenerated bool
+	// tests reports findings in _test.go files, which are skipped when unset
+	tests bool
+	// txReleasesStmts treats the statements prepared on a transaction as closed by its end
+	txReleasesStmts bool
+	// excludeGeneratedBy skips the files generated by these generators
+	excludeGeneratedBy stringsFlag
+	// closers are the imported functions closing the targets at the parameter indexes
+	closers map[types.Object][]int
+	// debug traces the verdict on every target to stderr
+	debug bool
+	// depthLimit caps the checkClosed recursion, deeper follow-through assumes the target is closed
+	depthLimit int
+	// depth of the checkClosed recursion and the deepest seen during the pass
+	depth, maxDepth int
+	// globalLoads are the loads of the package globals, which don't track their referrers
+	globalLoads map[*ssa.Global][]*ssa.UnOp
+	// modern decides whether targets are closed with the path-based dataflow
+	modern bool
+	// depthLimited is set when the follow-through of the current target hit depthLimit
+	depthLimited bool
+	// following are the functions whose parameters are being followed, guarding recursion
+	following map[*ssa.Function]bool
+	// merging are the phis being followed, guarding loops
+	merging map[*ssa.Phi]bool
+	// targetTypes of the pass and whether the types compared with them are one of them
+	targetTypes     []any
+	targetTypeCache map[types.Type]bool
+	// typeComparisons counts the types.Identical calls made filling targetTypeCache
+	typeComparisons int
+	// reachable are the blocks of each function reachable from its entry
+	reachable map[*ssa.Function]map[*ssa.BasicBlock]bool
+}
 
 func NewDeferOnlyAnalyzer() *analysis.Analyzer {
-	analyzer := &deferOnlyAnalyzer{}
 	flags := flag.NewFlagSet("deferOnlyAnalyzer", flag.ExitOnError)
+	analyzer := newDeferOnlyAnalyzer(flags)
 	return newAnalyzer(analyzer.Run, flags)
 }
 
+// NewDeferAnalyzer returns an analyzer reporting only the Close that should be
+// deferred. Run it beside an analyzer with -require-defer=false to enable or
+// disable the stylistic finding independently of the missing Close.
+func NewDeferAnalyzer() *analysis.Analyzer {
+	flags := flag.NewFlagSet("deferAnalyzer", flag.ExitOnError)
+	analyzer := newDeferOnlyAnalyzer(flags)
+	analyzer.onlyCheck.value = checkDefer
+	checker := newAnalyzer(analyzer.Run, flags)
+	checker.Name = "sqlclosecheckdefer"
+	checker.Doc = "Checks that the Close of sql.Rows, sql.Stmt, sqlx.NamedStmt, pgx.Query is deferred."
+	return checker
+}
+
+// newDeferOnlyAnalyzer returns a defer-only analyzer with its flags registered on flags
+func newDeferOnlyAnalyzer(flags *flag.FlagSet) *deferOnlyAnalyzer {
+	analyzer := &deferOnlyAnalyzer{
+		checks:     registerChecks(flags),
+		onlyCheck:  choiceFlag{choices: checkNames()},
+		depthLimit: defaultDepthLimit,
+		severities: severityFlag{},
+		changed:    changedLines{},
+		returnedPolicy: choiceFlag{
+			value:   returnedTrust,
+			choices: []string{returnedTrust, returnedWarn, returnedVerify},
+		},
+	}
+	flags.Var(&analyzer.onlyCheck, "only-category",
+		"Run only this check (e.g. unclosed or defer) and skip the others, see -list-checks")
+	flags.Var(analyzer.severities, "severity",
+		"Comma-separated severities per target type as pkgpath:Type:severity, "+
+			"e.g. database/sql:Rows:warning. Severity is error (default), warning or info.")
+	flags.Var(&analyzer.extraPackages, "packages",
+		"Comma-separated packages (e.g. github.com/org/driver) to check in addition to the built-in ones. "+
+			"Their types with a Close() error method are checked.")
+	flags.Var(&analyzer.packagesOnly, "packages-only",
+		"Comma-separated packages to check instead of the built-in ones")
+	flags.Var(&analyzer.targetTypeNames, "target-type",
+		"Comma-separated types (e.g. github.com/org/dbutil.Result) to check in addition to Rows/Stmt/NamedStmt, "+
+			"such as wrappers returned from a query. The types must have a Close method.")
+	flags.Var(&analyzer.closeMethods, "close-methods",
+		"Comma-separated method names (e.g. Release,Shutdown) that close a target in addition to Close")
+	flags.Var(&analyzer.ownershipFuncs, "ownership-func",
+		"Comma-separated functions (e.g. github.com/org/dbutil.MultiCloser) that take ownership of the targets "+
+			"passed to them. A closable value they return must be closed instead.")
+	flags.Var(&analyzer.cleanupFuncs, "cleanup-func",
+		"Comma-separated functions (e.g. github.com/org/dbutil.RegisterCleanup) that arrange for the func "+
+			"passed to them to be called later. A closure closing the targets passed to them closes the targets.")
+	// -debug itself is taken by the analysis drivers
+	flags.BoolVar(&analyzer.debug, "debug-targets", false, "Trace the analysis of every target to stderr")
+	flags.IntVar(&analyzer.depthLimit, "max-depth", defaultDepthLimit,
+		"Maximum depth of the follow-through of a target, deeper uses are assumed to close it")
+	flags.BoolVar(&analyzer.modern, "modern", false,
+		"Decide whether targets are closed with a dataflow over every path from their creation, instead of "+
+			"following their referrers")
+	flags.BoolVar(&analyzer.txReleasesStmts, "tx-releases-stmts", false,
+		"Treat a Stmt prepared on a transaction as closed when the transaction is rolled back or committed")
+	flags.BoolVar(&analyzer.includeGenerated, "include-generated", false,
+		"Report findings in generated files, e.g. sqlc or sqlboiler code, which are skipped by default")
+	flags.BoolVar(&analyzer.tests, "tests", true,
+		"Report findings in _test.go files, e.g. set to false when tests rely on t.Cleanup or the end of the test")
+	flags.Var(negatedFlag{&analyzer.includeGenerated}, "skip-generated",
+		"Deprecated: use -include-generated=false, which it is an alias of. Generated files are skipped by default.")
+	flags.Var(&analyzer.excludeGeneratedBy, "exclude-generated-by",
+		"Comma-separated generators (e.g. sqlc,protoc) whose generated files aren't reported with -include-generated, "+
+			"matched against the start of the name in the \"Code generated by\" comment")
+	flags.Var(analyzer.changed, "changed-lines",
+		"Only report findings on changed lines, given as comma-separated file:start-end ranges "+
+			"(e.g. db/users.go:10-25,db/orders.go:7) or as the path of a unified diff")
+	flags.Var(&analyzer.returnedPolicy, "returned-policy",
+		"How returned Rows/Stmt are treated: trust the caller to close them (trust), "+
+			"note that the caller must close them (warn) or verify that a caller in the package closes them (verify)")
+	return analyzer
+}
+
 // Run implements the main analysis pass
 func (a *deferOnlyAnalyzer) Run(pass *analysis.Pass) (interface{}, error) {
+	a = a.withImportedClosers(pass)
+	rep := a.newReporter(pass)
+
 	pssa, ok := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
 	if !ok {
-		return nil, nil
+		return rep.result, nil
 	}
 
 	// Build list of types we are looking for
-	targetTypes := getTargetTypes(pssa, sqlPackages)
+	targetTypes := getTargetTypes(pssa, a.targetPackages())
+	targetTypes = append(targetTypes, getPackageTargetTypes(pssa, a.userPackages(), targetTypes)...)
+	targetTypes = append(targetTypes, getCustomTargetTypes(pssa, a.targetTypeNames, a.closeMethods)...)
 
 	// If non of the types are found, skip
 	if len(targetTypes) == 0 {
-		return nil, nil
+		return rep.result, nil
 	}
 
 	funcs := pssa.SrcFuncs
+	rep.result.Stats.Functions = len(funcs)
+	a.globalLoads = findGlobalLoads(funcs)
+	a.following = map[*ssa.Function]bool{}
+	a.merging = map[*ssa.Phi]bool{}
+	a.targetTypes = targetTypes
+	a.targetTypeCache = map[types.Type]bool{}
+	a.reachable = map[*ssa.Function]map[*ssa.BasicBlock]bool{}
+	var callers map[*ssa.Function][]*ssa.Call
+	if a.returnedPolicy.value == returnedVerify {
+		callers = findCallers(funcs)
+	}
+
 	for _, f := range funcs {
 		for _, b := range f.Blocks {
 			for i := range b.Instrs {
 				// Check if instruction is call that returns a target pointer type
-				targetValues := getTargetTypesValues(b, i, targetTypes)
+				targetValues := a.getTargetTypesValues(b, i, targetTypes)
 				if len(targetValues) == 0 {
 					continue
 				}
 
 				// For each found target check if they are closed and deferred
 				for _, targetValue := range targetValues {
+					if !a.tests && isTestFile(pass, targetValue.instr.Pos()) {
+						continue
+					}
+
+					rep.result.Stats.Targets++
+					target := (*targetValue.value).Type()
 					refs := (*targetValue.value).Referrers()
-					isClosed := checkClosed(refs, targetTypes)
-					if !isClosed {
-						pass.Reportf((targetValue.instr).Pos(), "Rows/Stmt/NamedStmt was not closed")
+
+					// A transaction isn't closed but committed or rolled back, the checks of
+					// closes don't apply to it
+					if isTxType(target) {
+						if a.runs(checkUnfinishedTx) && (!a.checkClosed(*targetValue.value, targetTypes) || a.leaksOnSomePath(targetValue, targetTypes)) {
+							rep.reportf(checkUnfinishedTx, target, (targetValue.instr).Pos(), "Tx was neither committed nor rolled back")
+						}
+
+						continue
+					}
+
+					// Unused rows and the recursion limit are found by the traversal of the unclosed check
+					if a.runs(checkUnclosed) || a.runs(checkUnusedRows) || a.runs(checkDepthLimit) {
+						a.depthLimited = false
+						var isClosed bool
+						if a.modern {
+							isClosed = a.closedOnEveryPath(targetValue, targetTypes)
+						} else {
+							isClosed = a.checkClosed(*targetValue.value, targetTypes) && !a.leaksOnSomePath(targetValue, targetTypes)
+						}
+						if !isClosed && a.txReleasesStmts {
+							isClosed = txReleased(targetValue)
+						}
+						a.debugf("%s: %s closed=%t", pass.Fset.Position(targetValue.instr.Pos()), target, isClosed)
+						if !isClosed {
+							if a.runs(checkUnusedRows) && isUnusedRows(*targetValue.value) {
+								rep.reportf(checkUnusedRows, target, (targetValue.instr).Pos(), "Rows are never iterated nor closed, use Exec for statements that return no rows")
+							} else if a.runs(checkUnclosed) {
+								d := analysis.Diagnostic{
+									Pos:     (targetValue.instr).Pos(),
+									Message: unclosedMessage(target),
+									Related: a.leakFlow(targetValue, targetTypes),
+								}
+								// A close that isn't deferred gets a fix of its own
+								if !hasCloseCall(*targetValue.value) {
+									d.SuggestedFixes = addDeferCloseFix(pass, d.Pos)
+								}
+								rep.report(checkUnclosed, target, d)
+							}
+						}
+
+						if a.depthLimited && a.runs(checkDepthLimit) {
+							rep.reportfSeverity(checkDepthLimit, SeverityInfo, target, (targetValue.instr).Pos(),
+								"%s follow-through stopped at depth %d, assuming it is closed", qualifiedTypeName(target), a.depthLimit)
+						}
+					}
+
+					if a.runs(checkUnclosed) {
+						a.checkCopyClose(rep, *targetValue.value)
+						a.checkReturned(rep, *targetValue.value, targetTypes, callers)
+					}
+
+					if a.runs(checkFieldClose) {
+						a.checkFieldClose(rep, *targetValue.value, funcs, targetTypes)
+					}
+
+					if a.runs(checkDefer) {
+						a.checkDeferred(rep, target, targetValue, refs, targetTypes, false)
+					}
+
+					if a.runs(checkDoubleClose) {
+						a.checkDoubleClose(rep, *targetValue.value)
+					}
+
+					if a.runs(checkCloseErr) {
+						a.checkCloseErrInWrites(rep, *targetValue.value)
+					}
+
+					if a.runs(checkCloseError) {
+						a.checkCloseError(rep, *targetValue.value)
+					}
+
+					if a.runs(checkRowsErr) {
+						a.checkRowsErr(rep, *targetValue.value)
 					}
 
-					checkDeferred(pass, refs, targetTypes, false)
+					if a.runs(checkUnusedStmt) && isUnusedStmt(*targetValue.value) {
+						rep.reportf(checkUnusedStmt, target, (targetValue.instr).Pos(), "prepared Stmt is never used")
+					}
 				}
 			}
 		}
 	}
 
-	return nil, nil
+	a.exportClosers(pass, funcs, targetTypes)
+	rep.result.Stats.MaxDepth = a.maxDepth
+	rep.result.Stats.TypeComparisons = a.typeComparisons
+
+	return rep.result, nil
+}
+
+// isTestFile reports whether pos is in a _test.go file
+func isTestFile(pass *analysis.Pass, pos token.Pos) bool {
+	return strings.HasSuffix(pass.Fset.Position(pos).Filename, "_test.go")
+}
+
+// runs reports whether the check named name runs, -only-category overrides the
+// state of every check
+func (a *deferOnlyAnalyzer) runs(name string) bool {
+	if a.onlyCheck.value != "" {
+		return a.onlyCheck.value == name
+	}
+
+	return a.checks.on(name)
+}
+
+// isCloseMethod reports whether the method name closes a target, Close or one
+// of -close-methods
+func (a *deferOnlyAnalyzer) isCloseMethod(name string) bool {
+	return name == closeMethod || a.closeMethods.contains(name)
+}
+
+// debugf logs when -debug-targets is set, the analysis is silent otherwise
+func (a *deferOnlyAnalyzer) debugf(format string, args ...interface{}) {
+	if a.debug {
+		log.Printf(format, args...)
+	}
 }
 
 func getTargetTypes(pssa *buildssa.SSA, targetPackages []string) []any {
 	targets := []any{}
 
 	for _, sqlPkg := range targetPackages {
-		pkg := pssa.Pkg.Prog.ImportedPackage(sqlPkg)
+		pkg := importedPackage(pssa, sqlPkg)
 		if pkg == nil {
 			// the SQL package being checked isn't imported
 			continue
@@ -113,56 +413,297 @@ func getTargetTypes(pssa *buildssa.SSA, targetPackages []string) []any {
 			targets = append(targets, stmtType)
 		}
 
+		// A Stmt returned by value, e.g. bun.Stmt embedding *sql.Stmt
+		stmtValueType := getTypeFromName(pkg, stmtName)
+		if stmtValueType != nil {
+			targets = append(targets, stmtValueType)
+		}
+
 		namedStmtType := getTypePointerFromName(pkg, namedStmtName)
 		if namedStmtType != nil {
 			targets = append(targets, namedStmtType)
 		}
+
+		// An Iterator interface over the result of a query, e.g. dbr's SelectStmt.Iterate
+		iteratorType := getTypeFromName(pkg, iteratorName)
+		if iteratorType != nil && types.IsInterface(iteratorType) {
+			targets = append(targets, iteratorType)
+		}
+
+		if connPackages[sqlPkg] {
+			connType := getTypePointerFromName(pkg, connName)
+			if connType != nil {
+				targets = append(targets, connType)
+			}
+		}
+
+		txType := getTxTypeFromName(pkg)
+		if txType != nil {
+			targets = append(targets, txType)
+		}
+
+		if driverPackages[sqlPkg] {
+			targets = append(targets, getPackageTargetTypes(pssa, []string{sqlPkg}, targets)...)
+		}
+
+		if unexportedDriverPackages[sqlPkg] {
+			targets = append(targets, driverInterfaceTypes(pssa, targets)...)
+		}
+	}
+
+	return targets
+}
+
+// driverInterfaceTypes returns the Rows, Stmt and Conn interfaces of
+// database/sql/driver that aren't targets yet
+func driverInterfaceTypes(pssa *buildssa.SSA, targetTypes []any) []any {
+	pkg := importedPackage(pssa, "database/sql/driver")
+	if pkg == nil {
+		return nil
+	}
+
+	targets := []any{}
+	for _, name := range []string{rowsName, stmtName, connName} {
+		t := getTypeFromName(pkg, name)
+		if t != nil && !isTargetType(t, targetTypes) {
+			targets = append(targets, t)
+		}
+	}
+
+	return targets
+}
+
+// unclosedMessage returns the message reporting that the target of type t
+// isn't closed, e.g. database/sql.Rows was not closed
+func unclosedMessage(t types.Type) string {
+	return qualifiedTypeName(t) + " was not closed"
+}
+
+// targetPackages returns the packages whose Rows/Stmt/NamedStmt are checked
+func (a *deferOnlyAnalyzer) targetPackages() []string {
+	switch {
+	case len(a.packages) != 0:
+		return a.packages
+	case len(a.packagesOnly) != 0:
+		return a.packagesOnly
+	}
+
+	return append(append([]string{}, sqlPackages...), a.extraPackages...)
+}
+
+// userPackages returns the packages given by -packages or -packages-only that
+// aren't built in, whose closable types are all checked
+func (a *deferOnlyAnalyzer) userPackages() []string {
+	builtIn := map[string]bool{}
+	for _, pkg := range sqlPackages {
+		builtIn[pkg] = true
+	}
+
+	pkgs := []string{}
+	for _, pkg := range append(append([]string{}, a.extraPackages...), a.packagesOnly...) {
+		if !builtIn[pkg] {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+
+	return pkgs
+}
+
+// getPackageTargetTypes returns the named types of the packages that have a
+// Close() error method, those already in targetTypes are skipped
+func getPackageTargetTypes(pssa *buildssa.SSA, packages []string, targetTypes []any) []any {
+	targets := []any{}
+
+	for _, path := range packages {
+		pkg := importedPackage(pssa, path)
+		if pkg == nil {
+			continue
+		}
+
+		for _, name := range pkg.Scope().Names() {
+			obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+			if !ok {
+				continue
+			}
+
+			named, ok := obj.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+
+			for _, t := range []types.Type{named, types.NewPointer(named)} {
+				if !closesWithError(t) || isTargetType(t, targetTypes) || isTargetType(t, targets) {
+					continue
+				}
+
+				targets = append(targets, t)
+			}
+		}
+	}
+
+	return targets
+}
+
+// closesWithError reports whether t has a Close() error method
+func closesWithError(t types.Type) bool {
+	sel := types.NewMethodSet(t).Lookup(nil, closeMethod)
+	if sel == nil {
+		return false
+	}
+
+	fn, ok := sel.Obj().(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 0 && sig.Results().Len() == 1 &&
+		types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type())
+}
+
+// getCustomTargetTypes returns the types for the fully qualified names, the
+// types of packages that aren't imported are skipped. The types are closed by
+// Close or one of closeMethods.
+func getCustomTargetTypes(pssa *buildssa.SSA, names, closeMethods []string) []any {
+	targets := []any{}
+
+	for _, name := range names {
+		idx := strings.LastIndex(name, ".")
+		if idx <= 0 {
+			continue
+		}
+
+		pkgPath, typeName := name[:idx], name[idx+1:]
+		pkg := importedPackage(pssa, pkgPath)
+		if pkg == nil && pssa.Pkg.Pkg.Path() == pkgPath {
+			pkg = pssa.Pkg.Pkg
+		}
+		if pkg == nil {
+			continue
+		}
+
+		if ptrType := getTypePointerFromName(pkg, typeName, closeMethods...); ptrType != nil {
+			targets = append(targets, ptrType)
+		}
+
+		if namedType := getTypeFromName(pkg, typeName, closeMethods...); namedType != nil {
+			targets = append(targets, namedType)
+		}
 	}
 
 	return targets
 }
 
-func getTypePointerFromName(pkg *ssa.Package, name string) *types.Pointer {
-	pkgType := pkg.Type(name)
-	if pkgType == nil {
+// importedPackage returns the package with path imported, directly or not, by
+// the analyzed package. The SSA program may only hold the direct imports, while
+// the targets may come from an indirect one, e.g. *sql.Rows from bun.
+func importedPackage(pssa *buildssa.SSA, path string) *types.Package {
+	if pkg := pssa.Pkg.Prog.ImportedPackage(path); pkg != nil {
+		return pkg.Pkg
+	}
+
+	visited := map[*types.Package]bool{}
+	var find func(pkgs []*types.Package) *types.Package
+	find = func(pkgs []*types.Package) *types.Package {
+		for _, pkg := range pkgs {
+			if visited[pkg] {
+				continue
+			}
+			visited[pkg] = true
+
+			if pkg.Path() == path {
+				return pkg
+			}
+
+			if found := find(pkg.Imports()); found != nil {
+				return found
+			}
+		}
+
+		return nil
+	}
+
+	return find(pssa.Pkg.Pkg.Imports())
+}
+
+func getTypePointerFromName(pkg *types.Package, name string, closeMethods ...string) *types.Pointer {
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
 		// this package does not use Rows/Stmt/NamedStmt
 		return nil
 	}
 
-	obj := pkgType.Object()
 	named, ok := obj.Type().(*types.Named)
 	if !ok {
 		return nil
 	}
 
-	return types.NewPointer(named)
+	ptr := types.NewPointer(named)
+	if !hasCloseMethod(ptr, closeMethods...) {
+		// a type that shares the name but can't be closed
+		return nil
+	}
+
+	return ptr
 }
 
-func getTypeFromName(pkg *ssa.Package, name string) *types.Named {
-	pkgType := pkg.Type(name)
-	if pkgType == nil {
+func getTypeFromName(pkg *types.Package, name string, closeMethods ...string) *types.Named {
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
 		// this package does not use Rows/Stmt
 		return nil
 	}
 
-	obj := pkgType.Object()
 	named, ok := obj.Type().(*types.Named)
 	if !ok {
 		return nil
 	}
 
+	if !hasCloseMethod(named, closeMethods...) {
+		return nil
+	}
+
 	return named
 }
 
+// hasCloseMethod reports whether values of t, or pointers to them, have a Close
+// method, or one of closeMethods. Any signature counts, Close() without an error
+// closes as well.
+func hasCloseMethod(t types.Type, closeMethods ...string) bool {
+	for _, method := range append([]string{closeMethod}, closeMethods...) {
+		if types.NewMethodSet(t).Lookup(nil, method) != nil {
+			return true
+		}
+
+		if _, ok := t.Underlying().(*types.Interface); ok {
+			continue
+		}
+
+		if _, ok := t.(*types.Pointer); ok {
+			continue
+		}
+
+		if types.NewMethodSet(types.NewPointer(t)).Lookup(nil, method) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
 type targetValue struct {
 	value *ssa.Value
 	instr ssa.Instruction
 }
 
-func getTargetTypesValues(b *ssa.BasicBlock, i int, targetTypes []any) []targetValue {
+func (a *deferOnlyAnalyzer) getTargetTypesValues(b *ssa.BasicBlock, i int, targetTypes []any) []targetValue {
 	targetValues := []targetValue{}
 
 	instr := b.Instrs[i]
+	if assert, ok := instr.(*ssa.TypeAssert); ok {
+		return a.assertedTargetValues(assert)
+	}
+
 	call, ok := instr.(*ssa.Call)
 	if !ok {
 		return targetValues
@@ -173,6 +714,9 @@ func getTargetTypesValues(b *ssa.BasicBlock, i int, targetTypes []any) []targetV
 	for i := 0; i < results.Len(); i++ {
 		v := results.At(i)
 		varType := v.Type()
+		if !a.isTarget(varType) {
+			continue
+		}
 
 		for _, targetType := range targetTypes {
 			var tt types.Type
@@ -190,8 +734,18 @@ func getTargetTypesValues(b *ssa.BasicBlock, i int, targetTypes []any) []targetV
 				continue
 			}
 
+			embedded := false
 			for _, cRef := range *call.Referrers() {
 				switch instr := cRef.(type) {
+				case *ssa.FieldAddr:
+					// Used through its embedded closer only, e.g. the *pg.Conn of db.Conn()
+					if !embedded && types.Identical(call.Type(), tt) {
+						embedded = true
+						targetValues = append(targetValues, targetValue{
+							value: &instr.X,
+							instr: call,
+						})
+					}
 				case *ssa.Call:
 					if len(instr.Call.Args) >= 1 && types.Identical(instr.Call.Args[0].Type(), tt) {
 						targetValues = append(targetValues, targetValue{
@@ -199,6 +753,14 @@ func getTargetTypesValues(b *ssa.BasicBlock, i int, targetTypes []any) []targetV
 							instr: call,
 						})
 					}
+				case *ssa.Defer:
+					// Deferred on the fresh target, e.g. defer openRows().Close()
+					if len(instr.Call.Args) >= 1 && types.Identical(instr.Call.Args[0].Type(), tt) {
+						targetValues = append(targetValues, targetValue{
+							value: &instr.Call.Args[0],
+							instr: call,
+						})
+					}
 				case ssa.Value:
 					if types.Identical(instr.Type(), tt) {
 						targetValues = append(targetValues, targetValue{
@@ -214,10 +776,61 @@ func getTargetTypesValues(b *ssa.BasicBlock, i int, targetTypes []any) []targetV
 	return targetValues
 }
 
-func checkClosed(refs *[]ssa.Instruction, targetTypes []any) bool {
+// assertedTargetValues returns the target of a driver package asserted from a
+// driver interface, e.g. rows.(*sqlite3.SQLiteRows), or, asserted with comma
+// ok, its extraction
+func (a *deferOnlyAnalyzer) assertedTargetValues(assert *ssa.TypeAssert) []targetValue {
+	t := assert.AssertedType
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || !driverPackages[named.Obj().Pkg().Path()] || !a.isTarget(assert.AssertedType) {
+		return nil
+	}
+
+	if !assert.CommaOk {
+		value := ssa.Value(assert)
+		return []targetValue{{value: &value, instr: assert}}
+	}
+
+	for _, ref := range *assert.Referrers() {
+		if extract, ok := ref.(*ssa.Extract); ok && extract.Index == 0 {
+			value := ssa.Value(extract)
+			return []targetValue{{value: &value, instr: assert}}
+		}
+	}
+
+	return nil
+}
+
+func (a *deferOnlyAnalyzer) checkClosed(v ssa.Value, targetTypes []any) bool {
+	return a.checkInstrsClosed(v, v.Referrers(), targetTypes)
+}
+
+// checkInstrsClosed reports whether one of refs closes or hands over v. A nil v
+// stands for whatever target refs use, e.g. in the body of a closure.
+func (a *deferOnlyAnalyzer) checkInstrsClosed(v ssa.Value, refs *[]ssa.Instruction, targetTypes []any) bool {
+	a.depth++
+	defer func() { a.depth-- }()
+	if a.depth > a.maxDepth {
+		a.maxDepth = a.depth
+	}
+
+	if a.depthLimit > 0 && a.depth > a.depthLimit {
+		a.depthLimited = true
+		return true
+	}
+
 	numInstrs := len(*refs)
 	for idx, ref := range *refs {
-		action := getAction(ref, targetTypes)
+		// A close in dead code never runs
+		if a.unreachable(ref) {
+			continue
+		}
+
+		action := a.getAction(v, ref, targetTypes)
 		switch action {
 		case actionClosed, actionReturned, actionHandled:
 			return true
@@ -232,28 +845,42 @@ func checkClosed(refs *[]ssa.Instruction, targetTypes []any) bool {
 	return false
 }
 
-func getAction(instr ssa.Instruction, targetTypes []any) action {
+// getAction classifies instr, a referrer of v. v is nil when unknown.
+func (a *deferOnlyAnalyzer) getAction(v ssa.Value, instr ssa.Instruction, targetTypes []any) action {
 	switch instr := instr.(type) {
 	case *ssa.Defer:
 		if instr.Call.Value != nil {
 			name := instr.Call.Value.Name()
-			if name == closeMethod {
+			if a.isCloseMethod(name) {
 				return actionClosed
 			}
 		}
 
+		if endsTx(&instr.Call) {
+			return actionClosed
+		}
+
 		if instr.Call.Method != nil {
 			name := instr.Call.Method.Name()
-			if name == closeMethod {
+			if a.isCloseMethod(name) {
 				return actionClosed
 			}
 		} else if instr.Call.Value != nil {
 			// If it is a deferred function, go further down the call chain
 			if f, ok := instr.Call.Value.(*ssa.Function); ok {
-				for _, b := range f.Blocks {
-					if checkClosed(&b.Instrs, targetTypes) {
-						return actionHandled
-					}
+				// The arguments map to the parameters by position, others are ignored
+				if closesArg(funcBody(f), instr.Call.Args, v, targetTypes) {
+					return actionHandled
+				}
+
+				// Or closes the parameter further down, e.g. through a helper of its own
+				if a.passedClosed(funcBody(f), instr.Call.Args, v, targetTypes) {
+					return actionHandled
+				}
+
+				// Functions of other packages have no blocks, their facts tell if they close
+				if a.closesArgs(f, instr.Call.Args, targetTypes) {
+					return actionHandled
 				}
 			}
 		}
@@ -264,120 +891,840 @@ func getAction(instr ssa.Instruction, targetTypes []any) action {
 			return actionUnvaluedCall
 		}
 
+		// A method invoked on an interface target, e.g. pgx.Rows, has no static callee
+		if instr.Call.IsInvoke() && a.isTarget(instr.Call.Value.Type()) {
+			if a.isCloseMethod(instr.Call.Method.Name()) || endsTx(&instr.Call) {
+				return actionClosed
+			}
+
+			return actionUnhandled
+		}
+
 		isTarget := false
 		staticCallee := instr.Call.StaticCallee()
 		if staticCallee != nil {
 			receiver := instr.Call.StaticCallee().Signature.Recv()
 			if receiver != nil {
-				isTarget = isTargetType(receiver.Type(), targetTypes)
+				isTarget = a.isTarget(receiver.Type())
 			}
 		}
 
 		name := instr.Call.Value.Name()
-		if isTarget && name == closeMethod {
+		if isTarget && a.isCloseMethod(name) || endsTx(&instr.Call) {
 			return actionClosed
 		}
 
+		if staticCallee != nil && a.isOwnershipFunc(staticCallee) {
+			if a.checkOwnerClosed(instr, targetTypes) {
+				return actionHandled
+			}
+
+			return actionUnhandled
+		}
+
 		if !isTarget {
+			if staticCallee != nil {
+				body := funcBody(staticCallee)
+				if closesArg(body, instr.Call.Args, v, targetTypes) {
+					return actionHandled
+				}
+
+				// A wrapper that hands the target back is closed by closing its result
+				if returnsParam(body, argIndex(instr.Call.Args, v)) {
+					if a.checkClosed(instr, targetTypes) {
+						return actionHandled
+					}
+
+					return actionUnhandled
+				}
+
+				// A recursive call hands the target back to the function being followed
+				if a.following[body] {
+					return actionUnhandled
+				}
+
+				if a.passedClosed(body, instr.Call.Args, v, targetTypes) {
+					return actionHandled
+				}
+			}
+
 			return actionPassed
 		}
 	case *ssa.Phi:
-		return actionPassed
-	case *ssa.MakeInterface:
-		return actionPassed
-	case *ssa.Store:
-		// A Row/Stmt is stored in a struct, which may be closed later
-		// by a different flow.
-		if _, ok := instr.Addr.(*ssa.FieldAddr); ok {
-			return actionReturned
+		// Merged with other values, e.g. assigned in both branches of an if,
+		// the target is closed by closing the phi on every path after the merge
+		if a.merging[instr] {
+			return actionNoOp
 		}
+		a.merging[instr] = true
+		defer delete(a.merging, instr)
 
-		if len(*instr.Addr.Referrers()) == 0 {
+		merged := ssa.Value(instr)
+		if a.checkClosed(instr, targetTypes) && !a.leaksOnSomePath(targetValue{value: &merged, instr: instr}, targetTypes) {
+			return actionHandled
+		}
+
+		return actionUnhandled
+	case *ssa.MakeClosure:
+		// A bound Close (rows.Close) or a closure that is later invoked
+		if a.invokedClosing(instr, targetTypes, map[ssa.Value]bool{}) {
+			return actionHandled
+		}
+	case *ssa.MakeInterface:
+		// Closed through the interface it is converted to, e.g. io.Closer(rows).Close()
+		if closedThroughInterface(instr, map[ssa.Value]bool{}) {
+			return actionClosed
+		}
+
+		for _, ref := range *instr.Referrers() {
+			// Or through a method value of the interface, e.g. cleanup := closer.Close
+			if c, ok := ref.(*ssa.MakeClosure); ok && a.invokedClosing(c, targetTypes, map[ssa.Value]bool{}) {
+				return actionHandled
+			}
+		}
+
+		if call := a.ownershipCall(instr); call != nil {
+			if a.checkOwnerClosed(call, targetTypes) {
+				return actionHandled
+			}
+
+			return actionUnhandled
+		}
+
+		// Boxed into an interface field, e.g. io.Closer, that is closed later
+		if a.storedFieldClosed(instr, targetTypes) {
+			return actionHandled
+		}
+
+		// Passed variadically to a close-all helper, e.g. defer closeAll(rows, stmt)
+		if a.variadicClosed(instr, targetTypes) {
+			return actionHandled
+		}
+
+		// Stored in a sync.Map the target is handed over like to a struct field
+		if storedInSyncMap(instr) {
+			return actionReturned
+		}
+
+		return actionPassed
+	case *ssa.MapUpdate:
+		// A Row/Stmt is stored in a map whose elements are closed
+		if a.elementsClosed(instr.Map, targetTypes, map[ssa.Value]bool{}) {
+			return actionHandled
+		}
+
+		return actionUnhandled
+	case *ssa.Store:
+		// A Row/Stmt is stored in a struct, which may be closed later
+		// by a different flow. Unless the Close of the struct forgets it.
+		if field, ok := instr.Addr.(*ssa.FieldAddr); ok {
+			if a.closerForgetsField(field, targetTypes) {
+				return actionUnhandled
+			}
+
+			return actionReturned
+		}
+
+		// A Row/Stmt is stored in a package global, closed by whichever function loads it
+		if g, ok := instr.Addr.(*ssa.Global); ok {
+			// An exported global escapes the package, like a returned target
+			if g.Object() != nil && g.Object().Exported() {
+				return actionReturned
+			}
+
+			if a.globalClosed(g, targetTypes) {
+				return actionHandled
+			}
+
+			return actionUnhandled
+		}
+
+		// A Row/Stmt is stored in an array or slice whose elements are closed
+		if indexAddr, ok := instr.Addr.(*ssa.IndexAddr); ok {
+			if a.elementsClosed(indexAddr.X, targetTypes, map[ssa.Value]bool{}) || a.variadicClosed(instr.Val, targetTypes) {
+				return actionHandled
+			}
+
+			return actionUnhandled
+		}
+
+		if len(*instr.Addr.Referrers()) == 0 {
 			return actionNoOp
 		}
 
 		for _, aRef := range *instr.Addr.Referrers() {
-			if c, ok := aRef.(*ssa.MakeClosure); ok {
-				if f, ok := c.Fn.(*ssa.Function); ok {
-					for _, b := range f.Blocks {
-						if checkClosed(&b.Instrs, targetTypes) {
-							return actionHandled
-						}
-					}
+			// A closure that is never invoked doesn't close anything
+			if c, ok := aRef.(*ssa.MakeClosure); ok && a.closureInvoked(c) {
+				// Only the uses of this target count, the closure may close others as well
+				if freeVar := boundFreeVar(c, instr.Addr); freeVar != nil && a.checkClosed(freeVar, targetTypes) {
+					return actionHandled
 				}
 			}
 		}
 	case *ssa.UnOp:
-		instrType := instr.Type()
-		for _, targetType := range targetTypes {
-			var tt types.Type
-
-			switch t := targetType.(type) {
-			case *types.Pointer:
-				tt = t
-			case *types.Named:
-				tt = t
-			default:
-				continue
-			}
+		// Closing a copy of the target leaves the original open
+		if isCopy(instr) {
+			return actionUnhandled
+		}
 
-			if types.Identical(instrType, tt) {
-				if checkClosed(instr.Referrers(), targetTypes) {
-					return actionHandled
+		// The methods promoted from an embedded field are the target's, only Close closes it,
+		// or Commit and Rollback end it, e.g. of the *sql.Tx embedded in sqlx's Tx
+		if isEmbeddedLoad(instr) {
+			for _, ref := range *instr.Referrers() {
+				call, ok := ref.(ssa.CallInstruction)
+				if ok && call.Common().Value != nil && (a.isCloseMethod(call.Common().Value.Name()) || endsTx(call.Common())) {
+					return actionClosed
 				}
 			}
+
+			return actionUnhandled
+		}
+
+		if a.isTarget(instr.Type()) && a.checkClosed(instr, targetTypes) {
+			return actionHandled
 		}
 	case *ssa.FieldAddr:
-		if checkClosed(instr.Referrers(), targetTypes) {
+		if a.checkClosed(instr, targetTypes) {
+			return actionHandled
+		}
+	case *ssa.Field:
+		// A target returned by value closes through its embedded closer, e.g. bun.Stmt
+		if a.checkClosed(instr, targetTypes) {
 			return actionHandled
 		}
 	case *ssa.Return:
+		// Returned along with an error, the caller doesn't close it
+		if returnedWithError(instr) {
+			return actionUnhandled
+		}
+
 		if len(instr.Results) != 0 {
 			for _, result := range instr.Results {
-				resultType := result.Type()
-				for _, targetType := range targetTypes {
-					var tt types.Type
-
-					switch t := targetType.(type) {
-					case *types.Pointer:
-						tt = t
-					case *types.Named:
-						tt = t
-					default:
-						continue
+				if a.isTarget(result.Type()) {
+					return actionReturned
+				}
+			}
+		}
+	}
+
+	return actionUnhandled
+}
+
+// elementsClosed reports whether the elements of the array, slice or map coll
+// are loaded or ranged over and closed, in this function or in a closure
+// capturing coll. The slices appended to coll and the variables holding it are
+// followed, e.g. rowsList = append(rowsList, rows).
+func (a *deferOnlyAnalyzer) elementsClosed(coll ssa.Value, targetTypes []any, visited map[ssa.Value]bool) bool {
+	if visited[coll] {
+		return false
+	}
+	visited[coll] = true
+
+	// Loaded from a variable, which the other uses load it from as well
+	if load, ok := coll.(*ssa.UnOp); ok && load.Op == token.MUL && a.elementsClosed(load.X, targetTypes, visited) {
+		return true
+	}
+
+	for _, ref := range *coll.Referrers() {
+		switch ref := ref.(type) {
+		case *ssa.IndexAddr:
+			for _, iRef := range *ref.Referrers() {
+				if load, ok := iRef.(*ssa.UnOp); ok && load.Op == token.MUL && a.checkClosed(load, targetTypes) {
+					return true
+				}
+			}
+		case *ssa.Lookup:
+			if !ref.CommaOk && a.checkClosed(ref, targetTypes) {
+				return true
+			}
+		case *ssa.Range:
+			if a.rangedClosed(ref, targetTypes) {
+				return true
+			}
+		case *ssa.Slice, *ssa.Phi, *ssa.UnOp:
+			if a.elementsClosed(ref.(ssa.Value), targetTypes, visited) {
+				return true
+			}
+		case *ssa.Call:
+			// Appended to, the result holds the elements of coll
+			if b, ok := ref.Call.Value.(*ssa.Builtin); ok && b.Name() == "append" && a.elementsClosed(ref, targetTypes, visited) {
+				return true
+			}
+		case *ssa.Store:
+			// Held by a variable, e.g. one captured by a deferred closure
+			if ref.Val == coll && a.elementsClosed(ref.Addr, targetTypes, visited) {
+				return true
+			}
+		case *ssa.MakeClosure:
+			if freeVar := boundFreeVar(ref, coll); freeVar != nil && a.elementsClosed(freeVar, targetTypes, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// rangedClosed reports whether the values of the map ranged over by r are closed
+func (a *deferOnlyAnalyzer) rangedClosed(r *ssa.Range, targetTypes []any) bool {
+	for _, ref := range *r.Referrers() {
+		next, ok := ref.(*ssa.Next)
+		if !ok {
+			continue
+		}
+
+		for _, nRef := range *next.Referrers() {
+			// The tuple of a map iteration is (ok, key, value)
+			if extract, ok := nRef.(*ssa.Extract); ok && extract.Index == 2 && a.checkClosed(extract, targetTypes) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// variadicClosed reports whether v is one of the variadic arguments of a call
+// or defer whose callee closes the elements of its variadic parameter
+func (a *deferOnlyAnalyzer) variadicClosed(v ssa.Value, targetTypes []any) bool {
+	for _, ref := range *v.Referrers() {
+		store, ok := ref.(*ssa.Store)
+		if !ok || store.Val != v {
+			continue
+		}
+
+		indexAddr, ok := store.Addr.(*ssa.IndexAddr)
+		if !ok {
+			continue
+		}
+
+		alloc, ok := indexAddr.X.(*ssa.Alloc)
+		if !ok {
+			continue
+		}
+
+		for _, aRef := range *alloc.Referrers() {
+			slice, ok := aRef.(*ssa.Slice)
+			if !ok {
+				continue
+			}
+
+			for _, sRef := range *slice.Referrers() {
+				call, ok := callCommon(sRef)
+				if !ok || call.StaticCallee() == nil || !call.Signature().Variadic() {
+					continue
+				}
+
+				body := funcBody(call.StaticCallee())
+				last := len(call.Args) - 1
+				if len(body.Params) == len(call.Args) && call.Args[last] == slice &&
+					a.elementsClosed(body.Params[last], targetTypes, map[ssa.Value]bool{}) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// boundFreeVar returns the free variable of the closure c bound to v
+func boundFreeVar(c *ssa.MakeClosure, v ssa.Value) *ssa.FreeVar {
+	fn, ok := c.Fn.(*ssa.Function)
+	if !ok {
+		return nil
+	}
+
+	for i, binding := range c.Bindings {
+		if binding == v {
+			return fn.FreeVars[i]
+		}
+	}
+
+	return nil
+}
+
+// storedFieldClosed reports whether v is stored in a field of a struct and
+// the same field of that struct is loaded and closed. Nested selectors, e.g.
+// s.page.cursor.c, address the field anew on each use and are matched by path.
+func (a *deferOnlyAnalyzer) storedFieldClosed(v ssa.Value, targetTypes []any) bool {
+	for _, field := range fieldStores(v) {
+		for _, b := range field.Parent().Blocks {
+			for _, instr := range b.Instrs {
+				load, ok := instr.(*ssa.FieldAddr)
+				if !ok || !sameSelector(load, field) {
+					continue
+				}
+
+				for _, fRef := range *load.Referrers() {
+					if value, ok := fRef.(*ssa.UnOp); ok && value.Op == token.MUL && a.checkClosed(value, targetTypes) {
+						return true
 					}
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// sameSelector reports whether the field addresses x and y select the same
+// fields, one after the other, from the same value
+func sameSelector(x, y *ssa.FieldAddr) bool {
+	if x.Field != y.Field {
+		return false
+	}
+
+	if x.X == y.X {
+		return true
+	}
+
+	xParent, ok := x.X.(*ssa.FieldAddr)
+	if !ok {
+		return false
+	}
+
+	yParent, ok := y.X.(*ssa.FieldAddr)
+	return ok && sameSelector(xParent, yParent)
+}
 
-					if types.Identical(resultType, tt) {
-						return actionReturned
+// syncMapStores are the sync.Map methods storing their value argument
+var syncMapStores = []string{"Store", "LoadOrStore", "Swap", "CompareAndSwap"}
+
+// storedInSyncMap reports whether v is stored as a value of a sync.Map
+func storedInSyncMap(v ssa.Value) bool {
+	for _, ref := range *v.Referrers() {
+		call, ok := ref.(*ssa.Call)
+		if !ok {
+			continue
+		}
+
+		callee := call.Call.StaticCallee()
+		if callee == nil || callee.Signature.Recv() == nil || !strings.HasPrefix(callee.String(), "(*sync.Map).") {
+			continue
+		}
+
+		for _, method := range syncMapStores {
+			// The key comes first, the stored value is any of the arguments after it
+			if callee.Name() == method {
+				for _, arg := range call.Call.Args[2:] {
+					if arg == v {
+						return true
 					}
 				}
 			}
 		}
 	}
 
-	return actionUnhandled
+	return false
+}
+
+// defaultOwnershipFuncs are the well-known functions scanning and closing the rows passed to them
+var defaultOwnershipFuncs = scanyOwnershipFuncs()
+
+// scanyOwnershipFuncs returns the functions and API methods of scany, both
+// major versions, that close the rows they scan
+func scanyOwnershipFuncs() []string {
+	funcs := []string{}
+	for _, module := range []string{"github.com/georgysavva/scany", "github.com/georgysavva/scany/v2"} {
+		for _, pkg := range []string{"sqlscan", "pgxscan"} {
+			for _, name := range []string{"ScanAll", "ScanOne", "ScanAllSets"} {
+				funcs = append(funcs,
+					module+"/"+pkg+"."+name,
+					"(*"+module+"/"+pkg+".API)."+name,
+				)
+			}
+		}
+	}
+
+	return funcs
+}
+
+func (a *deferOnlyAnalyzer) isOwnershipFunc(fn *ssa.Function) bool {
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+
+	name := fn.String()
+	for _, owner := range defaultOwnershipFuncs {
+		if name == owner {
+			return true
+		}
+	}
+
+	return a.ownershipFuncs.contains(name)
+}
+
+// ownershipCall returns the ownership func call v is passed to, either directly
+// or as part of the variadic arguments
+func (a *deferOnlyAnalyzer) ownershipCall(v ssa.Value) *ssa.Call {
+	for _, ref := range *v.Referrers() {
+		switch ref := ref.(type) {
+		case *ssa.Call:
+			if callee := ref.Call.StaticCallee(); callee != nil && a.isOwnershipFunc(callee) {
+				return ref
+			}
+		case *ssa.Store:
+			indexAddr, ok := ref.Addr.(*ssa.IndexAddr)
+			if !ok {
+				continue
+			}
+
+			alloc, ok := indexAddr.X.(*ssa.Alloc)
+			if !ok {
+				continue
+			}
+
+			for _, aRef := range *alloc.Referrers() {
+				if slice, ok := aRef.(*ssa.Slice); ok {
+					if call := a.ownershipCall(slice); call != nil {
+						return call
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkOwnerClosed reports whether the closable value returned by an ownership
+// func is closed. Ownership funcs that return nothing closable are trusted to
+// close the targets themselves.
+func (a *deferOnlyAnalyzer) checkOwnerClosed(call *ssa.Call, targetTypes []any) bool {
+	if _, ok := call.Type().(*types.Tuple); !ok {
+		if !hasCloseMethod(call.Type()) {
+			return true
+		}
+
+		return a.checkClosed(call, targetTypes)
+	}
+
+	tuple := call.Type().(*types.Tuple)
+	closable := false
+	for i := 0; i < tuple.Len(); i++ {
+		closable = closable || hasCloseMethod(tuple.At(i).Type())
+	}
+
+	if !closable {
+		return true
+	}
+
+	for _, ref := range *call.Referrers() {
+		extract, ok := ref.(*ssa.Extract)
+		if !ok || !hasCloseMethod(extract.Type()) {
+			continue
+		}
+
+		if a.checkClosed(extract, targetTypes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// invokedClosing reports whether the function value v, or a phi merging it, is
+// called or deferred and every function value it may hold closes a target
+func (a *deferOnlyAnalyzer) invokedClosing(v ssa.Value, targetTypes []any, visited map[ssa.Value]bool) bool {
+	if visited[v] {
+		return false
+	}
+	visited[v] = true
+
+	for _, ref := range *v.Referrers() {
+		switch ref := ref.(type) {
+		case *ssa.Defer:
+			if ref.Call.Value == v && a.closesWhenInvoked(v, targetTypes, map[ssa.Value]bool{}) {
+				return true
+			}
+		case *ssa.Call:
+			if ref.Call.Value == v && a.closesWhenInvoked(v, targetTypes, map[ssa.Value]bool{}) {
+				return true
+			}
+
+			// Registered by a cleanup helper to be called later
+			if ref.Call.Value != v && a.invokesArg(&ref.Call, v, map[*ssa.Function]bool{}) &&
+				a.closesWhenInvoked(v, targetTypes, map[ssa.Value]bool{}) {
+				return true
+			}
+		case *ssa.Phi:
+			if a.invokedClosing(ref, targetTypes, visited) {
+				return true
+			}
+		case *ssa.Store:
+			// Held by a variable captured by a closure calling it, e.g. defer func() { _ = cleanup() }()
+			if ref.Val == v && a.capturedCalled(ref.Addr) && a.closesWhenInvoked(v, targetTypes, map[ssa.Value]bool{}) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// capturedCalled reports whether the variable addr is captured by a closure
+// that is invoked and calls the function value loaded from addr
+func (a *deferOnlyAnalyzer) capturedCalled(addr ssa.Value) bool {
+	for _, ref := range *addr.Referrers() {
+		c, ok := ref.(*ssa.MakeClosure)
+		if !ok || !a.closureInvoked(c) {
+			continue
+		}
+
+		freeVar := boundFreeVar(c, addr)
+		if freeVar == nil {
+			continue
+		}
+
+		for _, fRef := range *freeVar.Referrers() {
+			load, ok := fRef.(*ssa.UnOp)
+			if !ok || load.Op != token.MUL {
+				continue
+			}
+
+			for _, lRef := range *load.Referrers() {
+				if call, ok := callCommon(lRef); ok && call.Value == load {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// closesWhenInvoked reports whether calling the function value v closes a target.
+// For a phi every edge has to close, for a call every function value returned
+// by the callee.
+func (a *deferOnlyAnalyzer) closesWhenInvoked(v ssa.Value, targetTypes []any, visited map[ssa.Value]bool) bool {
+	if visited[v] {
+		return false
+	}
+	visited[v] = true
+
+	switch v := v.(type) {
+	case *ssa.MakeClosure:
+		fn, ok := v.Fn.(*ssa.Function)
+		if !ok {
+			return false
+		}
+
+		// Method value bound to its receiver, e.g. rows.Close
+		if strings.HasSuffix(fn.Name(), "$bound") && a.isCloseMethod(strings.TrimSuffix(fn.Name(), "$bound")) {
+			if len(v.Bindings) == 0 {
+				return false
+			}
+
+			// Bound to the interface holding the target, e.g. io.Closer(rows).Close
+			recv := v.Bindings[0]
+			if mi, ok := recv.(*ssa.MakeInterface); ok {
+				recv = mi.X
+			}
+
+			return a.isTarget(recv.Type())
+		}
+
+		for _, b := range fn.Blocks {
+			if a.checkInstrsClosed(nil, &b.Instrs, targetTypes) {
+				return true
+			}
+		}
+	case *ssa.Phi:
+		for _, edge := range v.Edges {
+			if !a.closesWhenInvoked(edge, targetTypes, visited) {
+				return false
+			}
+		}
+
+		return len(v.Edges) > 0
+	case *ssa.Call:
+		callee := v.Call.StaticCallee()
+		if callee == nil {
+			return false
+		}
+
+		returned := false
+		for _, b := range callee.Blocks {
+			ret, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return)
+			if !ok || len(ret.Results) != 1 {
+				continue
+			}
+
+			if !a.closesWhenInvoked(ret.Results[0], targetTypes, visited) {
+				return false
+			}
+			returned = true
+		}
+
+		return returned
+	}
+
+	return false
+}
+
+// funcBody returns the function holding the body of fn. Instances of generic
+// functions built without instantiating generics are wrappers calling the
+// generic function.
+func funcBody(fn *ssa.Function) *ssa.Function {
+	if origin := fn.Origin(); origin != nil {
+		return origin
+	}
+
+	return fn
+}
+
+// passedClosed reports whether fn closes one of the targets in args, following
+// the parameter each maps to through the body of fn, e.g. into a helper it
+// passes the parameter on to. Only the arguments v is passed as count, unless
+// v is nil.
+func (a *deferOnlyAnalyzer) passedClosed(fn *ssa.Function, args []ssa.Value, v ssa.Value, targetTypes []any) bool {
+	if len(fn.Params) != len(args) || a.following[fn] {
+		return false
+	}
+
+	a.following[fn] = true
+	defer delete(a.following, fn)
+
+	for i, arg := range args {
+		if v != nil && arg != v {
+			continue
+		}
+
+		if a.isTarget(arg.Type()) && a.checkClosed(fn.Params[i], targetTypes) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// closesArg reports whether fn closes one of the targets in args, mapping
+// each argument to its parameter, which may be of a type parameter. Only the
+// arguments v is passed as count, unless v is nil.
+func closesArg(fn *ssa.Function, args []ssa.Value, v ssa.Value, targetTypes []any) bool {
+	if len(fn.Params) != len(args) {
+		return false
+	}
+
+	for i, arg := range args {
+		if v != nil && arg != v {
+			continue
+		}
+
+		if isTargetType(arg.Type(), targetTypes) && closesParam(fn.Params[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// returnsParam reports whether fn returns its parameter at index i, either as
+// is or stored in a field of the struct it returns
+func returnsParam(fn *ssa.Function, i int) bool {
+	if i < 0 || i >= len(fn.Params) {
+		return false
+	}
+
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			ret, ok := instr.(*ssa.Return)
+			if !ok {
+				continue
+			}
+
+			for _, result := range ret.Results {
+				if isParamOrWrapsParam(result, fn.Params[i]) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func isParamOrWrapsParam(v ssa.Value, param *ssa.Parameter) bool {
+	switch v := v.(type) {
+	case *ssa.Parameter:
+		return v == param
+	case *ssa.UnOp:
+		// Struct returned by value is loaded from its local allocation
+		if alloc, ok := v.X.(*ssa.Alloc); ok {
+			return isParamOrWrapsParam(alloc, param)
+		}
+	case *ssa.Alloc:
+		for _, ref := range *v.Referrers() {
+			fieldAddr, ok := ref.(*ssa.FieldAddr)
+			if !ok {
+				continue
+			}
+
+			for _, fRef := range *fieldAddr.Referrers() {
+				if store, ok := fRef.(*ssa.Store); ok && store.Val == param {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
 }
 
-func checkDeferred(pass *analysis.Pass, instrs *[]ssa.Instruction, targetTypes []any, inDefer bool) {
+// argIndex returns the index of v in args, or -1
+func argIndex(args []ssa.Value, v ssa.Value) int {
+	for i, arg := range args {
+		if arg == v {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (a *deferOnlyAnalyzer) checkDeferred(rep *reporter, target types.Type, created targetValue, instrs *[]ssa.Instruction, targetTypes []any, inDefer bool) {
 	for _, instr := range *instrs {
 		switch instr := instr.(type) {
 		case *ssa.Defer:
-			if instr.Call.Value != nil && instr.Call.Value.Name() == closeMethod {
+			if instr.Call.Value != nil && a.isCloseMethod(instr.Call.Value.Name()) {
 				return
 			}
 
-			if instr.Call.Method != nil && instr.Call.Method.Name() == closeMethod {
+			if instr.Call.Method != nil && a.isCloseMethod(instr.Call.Method.Name()) {
 				return
 			}
 		case *ssa.Call:
-			if instr.Call.Value != nil && instr.Call.Value.Name() == closeMethod {
-				if !inDefer {
-					pass.Reportf(instr.Pos(), "Close should use defer")
+			if instr.Call.Value != nil && a.isCloseMethod(instr.Call.Value.Name()) ||
+				instr.Call.Method != nil && a.isCloseMethod(instr.Call.Method.Name()) {
+				// A defer in a loop would keep every iteration's target open until the function returns,
+				// and a deferred Close would close the target handed to the caller
+				if !inDefer && !inSameLoop(created.instr.Block(), instr.Block()) && !handedToCaller(*created.value) {
+					d := analysis.Diagnostic{
+						Pos:     instr.Pos(),
+						Message: "Close should use defer",
+					}
+					// With a deferred Close as well, the Close is redundant rather than not deferred
+					if !hasDeferredClose(*created.value) {
+						d.SuggestedFixes = convertToDeferFix(rep.pass, created.instr.Pos(), instr.Pos())
+					}
+					rep.report(checkDefer, target, d)
 				}
 
 				return
 			}
 		case *ssa.Store:
+			// Only the loads of a global in this function can defer its close
+			if g, ok := instr.Addr.(*ssa.Global); ok {
+				for _, load := range a.globalLoads[g] {
+					if load.Parent() == instr.Parent() {
+						a.checkDeferred(rep, target, created, load.Referrers(), targetTypes, inDefer)
+					}
+				}
+
+				continue
+			}
+
 			if len(*instr.Addr.Referrers()) == 0 {
 				return
 			}
@@ -386,33 +1733,56 @@ func checkDeferred(pass *analysis.Pass, instrs *[]ssa.Instruction, targetTypes [
 				if c, ok := aRef.(*ssa.MakeClosure); ok {
 					if f, ok := c.Fn.(*ssa.Function); ok {
 						for _, b := range f.Blocks {
-							checkDeferred(pass, &b.Instrs, targetTypes, true)
+							a.checkDeferred(rep, target, created, &b.Instrs, targetTypes, true)
 						}
 					}
 				}
 			}
 		case *ssa.UnOp:
-			instrType := instr.Type()
-			for _, targetType := range targetTypes {
-				var tt types.Type
-
-				switch t := targetType.(type) {
-				case *types.Pointer:
-					tt = t
-				case *types.Named:
-					tt = t
-				default:
-					continue
-				}
+			if isEmbeddedLoad(instr) {
+				a.checkDeferred(rep, target, created, instr.Referrers(), targetTypes, inDefer)
+				continue
+			}
 
-				if types.Identical(instrType, tt) {
-					checkDeferred(pass, instr.Referrers(), targetTypes, inDefer)
-				}
+			if a.isTarget(instr.Type()) {
+				a.checkDeferred(rep, target, created, instr.Referrers(), targetTypes, inDefer)
 			}
 		case *ssa.FieldAddr:
-			checkDeferred(pass, instr.Referrers(), targetTypes, inDefer)
+			a.checkDeferred(rep, target, created, instr.Referrers(), targetTypes, inDefer)
+		case *ssa.MakeInterface:
+			// Converted to an interface, e.g. io.Closer(rows).Close()
+			a.checkDeferred(rep, target, created, instr.Referrers(), targetTypes, inDefer)
+		}
+	}
+}
+
+// isTarget reports whether t is one of the target types of the pass. The
+// result is memoized per type, as the same receiver and result types are
+// compared for every referrer of every target.
+func (a *deferOnlyAnalyzer) isTarget(t types.Type) bool {
+	if is, ok := a.targetTypeCache[t]; ok {
+		return is
+	}
+
+	is := false
+	for _, targetType := range a.targetTypes {
+		tt, ok := targetType.(types.Type)
+		if !ok {
+			continue
+		}
+
+		a.typeComparisons++
+		if types.Identical(t, tt) {
+			is = true
+			break
 		}
 	}
+
+	if a.targetTypeCache != nil {
+		a.targetTypeCache[t] = is
+	}
+
+	return is
 }
 
 func isTargetType(t types.Type, targetTypes []any) bool {
@@ -431,3 +1801,61 @@ func isTargetType(t types.Type, targetTypes []any) bool {
 
 	return false
 }
+
+// closedThroughInterface reports whether Close is invoked on the interface v, or
+// on an interface it is asserted or converted to, e.g. v.(io.Closer).Close()
+func closedThroughInterface(v ssa.Value, visited map[ssa.Value]bool) bool {
+	if visited[v] {
+		return false
+	}
+	visited[v] = true
+
+	for _, ref := range *v.Referrers() {
+		if _, ok := closeCall(ref, v); ok {
+			return true
+		}
+
+		switch ref := ref.(type) {
+		case *ssa.ChangeInterface, *ssa.ChangeType:
+			if closedThroughInterface(ref.(ssa.Value), visited) {
+				return true
+			}
+		case *ssa.TypeAssert:
+			if !types.IsInterface(ref.AssertedType) {
+				continue
+			}
+
+			if !ref.CommaOk {
+				if closedThroughInterface(ref, visited) {
+					return true
+				}
+				continue
+			}
+
+			for _, aRef := range *ref.Referrers() {
+				if extract, ok := aRef.(*ssa.Extract); ok && extract.Index == 0 && closedThroughInterface(extract, visited) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// isEmbeddedLoad reports whether instr loads an embedded field of a target, the
+// receiver of the methods promoted from it, e.g. the Close of go-pg's Conn
+func isEmbeddedLoad(instr *ssa.UnOp) bool {
+	fieldAddr, ok := instr.X.(*ssa.FieldAddr)
+	if !ok || instr.Op != token.MUL {
+		return false
+	}
+
+	ptr, ok := fieldAddr.X.Type().Underlying().(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	st, ok := ptr.Elem().Underlying().(*types.Struct)
+	return ok && st.Field(fieldAddr.Field).Embedded()
+}
@@ -17,6 +17,16 @@ func TestDeferOnlyAnalyzer(t *testing.T) {
 		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/rows",
 		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/stmt",
 		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/pgx",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/pgxpool",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/pgxv4",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/bun",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/gopg",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlite3",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlx",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/dbr",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/clickhouse",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlboiler/raw",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/mysql",
 	}
 
 	for _, pkg := range packages {
@@ -29,3 +39,338 @@ func TestDeferOnlyAnalyzer(t *testing.T) {
 		})
 	}
 }
+
+func TestDeferOnlyAnalyzerModern(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("modern", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	packages := []string{
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/rows",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/stmt",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/pgx",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/pgxpool",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/pgxv4",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/bun",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/gopg",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlite3",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlx",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/dbr",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/clickhouse",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlboiler/raw",
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/mysql",
+	}
+
+	for _, pkg := range packages {
+		pkg := pkg
+
+		t.Run(pkg, func(t *testing.T) {
+			t.Parallel()
+
+			analysistest.Run(t, testdata, checker, pkg)
+		})
+	}
+}
+
+func TestDeferOnlyAnalyzerNameCollision(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzerWithPackages(
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/collision/fakesql",
+	)
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/collision")
+}
+
+func TestDeferOnlyAnalyzerOwnershipFunc(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("ownership-func", "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/ownership.MultiCloser"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/ownership")
+}
+
+func TestDeferOnlyAnalyzerCustomRows(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzerWithPackages(
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/custom/driver",
+	)
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/custom")
+}
+
+func TestDeferOnlyAnalyzerCopyClose(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzerWithPackages(
+		"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/copyclose/driver",
+	)
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/copyclose")
+}
+
+func TestDeferOnlyAnalyzerUnusedRows(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("check-unused-rows", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/unusedrows")
+}
+
+func TestDeferOnlyAnalyzerUnusedStmt(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("check-unused-stmt", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/unusedstmt")
+}
+
+func TestDeferOnlyAnalyzerReturnedPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []string{"trust", "warn", "verify"} {
+		policy := policy
+
+		t.Run(policy, func(t *testing.T) {
+			t.Parallel()
+
+			testdata := analysistest.TestData()
+			checker := analyzer.NewDeferOnlyAnalyzer()
+			if err := checker.Flags.Set("returned-policy", policy); err != nil {
+				t.Fatal(err)
+			}
+
+			analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/returned/"+policy)
+		})
+	}
+}
+
+func TestDeferOnlyAnalyzerCrossPackage(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/crosspkg")
+}
+
+func TestDeferOnlyAnalyzerFieldClose(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("check-field-close", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/fieldclose")
+}
+
+func TestDeferOnlyAnalyzerTargetType(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("target-type", "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/wrapperresult/db.Result"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/wrapperresult")
+}
+
+func TestDeferOnlyAnalyzerCloseMethods(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	flags := map[string]string{
+		"target-type": "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/closemethods.Conn," +
+			"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/closemethods.Pool",
+		"close-methods": "Release,Shutdown",
+	}
+	for name, value := range flags {
+		if err := checker.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/closemethods")
+}
+
+func TestDeferOnlyAnalyzerGenerated(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.NewDeferOnlyAnalyzer(), "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlboiler/skipped")
+
+	included := analyzer.NewDeferOnlyAnalyzer()
+	if err := included.Flags.Set("include-generated", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, included, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlboiler/models")
+
+	// -skip-generated is a deprecated alias of -include-generated=false, the last one set wins
+	skipped := analyzer.NewDeferOnlyAnalyzer()
+	for _, f := range [][2]string{{"include-generated", "true"}, {"skip-generated", "true"}} {
+		if err := skipped.Flags.Set(f[0], f[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, testdata, skipped, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlboiler/skipped")
+
+	notSkipped := analyzer.NewDeferOnlyAnalyzer()
+	if err := notSkipped.Flags.Set("skip-generated", "false"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, notSkipped, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/sqlboiler/models")
+}
+
+func TestDeferOnlyAnalyzerExcludeGeneratedBy(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	for name, value := range map[string]string{"include-generated": "true", "exclude-generated-by": "sqlc,protoc"} {
+		if err := checker.Flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/generators")
+}
+
+func TestDeferOnlyAnalyzerMessages(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzer.NewDeferOnlyAnalyzer(), "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/messages")
+}
+
+func TestDeferOnlyAnalyzerTestsOff(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("tests", "false"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/tests")
+}
+
+func TestDeferOnlyAnalyzerIterSeq(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/iterseq")
+}
+
+func TestDeferOnlyAnalyzerCleanupFunc(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	cleanupFuncs := "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/cleanup.registerCleanup," +
+		"(*github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/cleanup.deferStack).Add"
+	if err := checker.Flags.Set("cleanup-func", cleanupFuncs); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/cleanup")
+}
+
+func TestDeferOnlyAnalyzerTxReleasesStmts(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("tx-releases-stmts", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/txstmts")
+}
+
+func TestDeferOnlyAnalyzerRecursionLimit(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	for flag, value := range map[string]string{"max-depth": "3", "warn-recursion-limit": "true"} {
+		if err := checker.Flags.Set(flag, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/depthlimit")
+}
+
+func TestDeferOnlyAnalyzerPackages(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("packages", "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/packages/driver"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/packages")
+}
+
+func TestDeferOnlyAnalyzerPackagesOnly(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("packages-only", "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/packages/driver"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/packagesonly")
+}
+
+func TestDeferOnlyAnalyzerNolint(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/nolint")
+}
+
+func TestDeferOnlyAnalyzerRowsErr(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("check-rows-err", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/rowserr")
+}
@@ -0,0 +1,253 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ssa"
+)
+
+// closeCall returns the call common to a Close call or defer on v, or to a
+// Commit or Rollback when v is a transaction
+func closeCall(instr ssa.Instruction, v ssa.Value) (*ssa.CallCommon, bool) {
+	if isTxType(v.Type()) {
+		for _, method := range txEnds {
+			if call, ok := methodCall(instr, v, method); ok {
+				return call, true
+			}
+		}
+
+		return nil, false
+	}
+
+	return methodCall(instr, v, closeMethod)
+}
+
+// methodCall returns the call common to a call or defer of the method on v
+func methodCall(instr ssa.Instruction, v ssa.Value, method string) (*ssa.CallCommon, bool) {
+	var call *ssa.CallCommon
+	switch instr := instr.(type) {
+	case *ssa.Call:
+		call = &instr.Call
+	case *ssa.Defer:
+		call = &instr.Call
+	default:
+		return nil, false
+	}
+
+	if call.IsInvoke() {
+		return call, call.Method.Name() == method && call.Value == v
+	}
+
+	callee := call.StaticCallee()
+	if callee == nil || callee.Name() != method || callee.Signature.Recv() == nil {
+		return nil, false
+	}
+
+	return call, len(call.Args) >= 1 && call.Args[0] == v
+}
+
+// hasCloseCall reports whether v is closed directly, deferred or not
+func hasCloseCall(v ssa.Value) bool {
+	for _, ref := range *v.Referrers() {
+		if _, ok := closeCall(ref, v); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasDeferredClose reports whether v has a deferred Close
+func hasDeferredClose(v ssa.Value) bool {
+	for _, ref := range *v.Referrers() {
+		if _, ok := ref.(*ssa.Defer); ok {
+			if _, ok := closeCall(ref, v); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkDoubleClose reports closes of v that always follow, or are followed by,
+// another close of v. A close in a branch that returns before a deferred close
+// is registered doesn't dominate the defer and isn't reported.
+func (a *deferOnlyAnalyzer) checkDoubleClose(rep *reporter, v ssa.Value) {
+	closes := closeInstrs(v)
+
+	reported := map[ssa.Instruction]bool{}
+	for i := 0; i < len(closes); i++ {
+		for j := i + 1; j < len(closes); j++ {
+			first, second := closes[i], closes[j]
+			if !dominates(first, second) {
+				if !dominates(second, first) {
+					continue
+				}
+				first, second = second, first
+			}
+
+			// Prefer keeping the deferred close
+			redundant := second
+			if _, ok := second.(*ssa.Defer); ok {
+				if _, ok := first.(*ssa.Defer); !ok {
+					redundant = first
+				}
+			}
+
+			if reported[redundant] {
+				continue
+			}
+			reported[redundant] = true
+
+			rep.report(checkDoubleClose, v.Type(), analysis.Diagnostic{
+				Pos:            redundant.Pos(),
+				Message:        "Rows/Stmt closed more than once",
+				SuggestedFixes: removeCloseFix(rep.pass, redundant.Pos()),
+			})
+		}
+	}
+}
+
+// closeInstrs returns the closes of v. A v captured by a closure is stored in a
+// variable, whose loads are closed, and a deferred closure closing it
+// unconditionally counts as a close where it is deferred.
+func closeInstrs(v ssa.Value) []ssa.Instruction {
+	closes := []ssa.Instruction{}
+	for _, ref := range *v.Referrers() {
+		if _, ok := closeCall(ref, v); ok {
+			closes = append(closes, ref)
+			continue
+		}
+
+		store, ok := ref.(*ssa.Store)
+		if !ok || store.Val != v {
+			continue
+		}
+
+		alloc, ok := store.Addr.(*ssa.Alloc)
+		if !ok {
+			continue
+		}
+
+		for _, aRef := range *alloc.Referrers() {
+			switch aRef := aRef.(type) {
+			case *ssa.UnOp:
+				for _, lRef := range *aRef.Referrers() {
+					if _, ok := closeCall(lRef, aRef); ok {
+						closes = append(closes, lRef)
+					}
+				}
+			case *ssa.MakeClosure:
+				freeVar := boundFreeVar(aRef, alloc)
+				if freeVar == nil || !closedOnEntry(freeVar) {
+					continue
+				}
+
+				for _, cRef := range *aRef.Referrers() {
+					if d, ok := cRef.(*ssa.Defer); ok && d.Call.Value == aRef {
+						closes = append(closes, d)
+					}
+				}
+			}
+		}
+	}
+
+	return closes
+}
+
+// closedOnEntry reports whether the variable freeVar of a closure is loaded
+// and closed in the entry block of the closure, a close that isn't guarded
+func closedOnEntry(freeVar *ssa.FreeVar) bool {
+	for _, ref := range *freeVar.Referrers() {
+		load, ok := ref.(*ssa.UnOp)
+		if !ok || load.Block().Index != 0 {
+			continue
+		}
+
+		for _, lRef := range *load.Referrers() {
+			if _, ok := closeCall(lRef, load); ok && lRef.Block().Index == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// dominates reports whether a executes before b on every path reaching b
+func dominates(a, b ssa.Instruction) bool {
+	if a.Block() != b.Block() {
+		return a.Block().Dominates(b.Block())
+	}
+
+	for _, instr := range a.Block().Instrs {
+		if instr == a {
+			return true
+		}
+		if instr == b {
+			return false
+		}
+	}
+
+	return false
+}
+
+// removeCloseFix returns a fix deleting the statement of the close at pos. A
+// close whose error is used, e.g. checked or returned, is left as is, as the
+// close kept, usually deferred, drops its error.
+func removeCloseFix(pass *analysis.Pass, pos token.Pos) []analysis.SuggestedFix {
+	file := fileOf(pass, pos)
+	if file == nil {
+		return nil
+	}
+
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+
+	var stmt ast.Stmt
+	for i, node := range path {
+		if _, ok := node.(*ast.CallExpr); !ok {
+			continue
+		}
+
+		if i+1 >= len(path) {
+			return nil
+		}
+
+		switch parent := path[i+1].(type) {
+		case *ast.ExprStmt:
+			stmt = parent
+		case *ast.DeferStmt:
+			stmt = parent
+		default:
+			return nil
+		}
+
+		break
+	}
+
+	if stmt == nil {
+		return nil
+	}
+
+	return []analysis.SuggestedFix{{
+		Message: "Remove redundant Close",
+		TextEdits: []analysis.TextEdit{{
+			Pos: stmt.Pos(),
+			End: stmt.End(),
+		}},
+	}}
+}
+
+func fileOf(pass *analysis.Pass, pos token.Pos) *ast.File {
+	for _, f := range pass.Files {
+		if f.Pos() <= pos && pos <= f.End() {
+			return f
+		}
+	}
+
+	return nil
+}
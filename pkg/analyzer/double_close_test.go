@@ -0,0 +1,20 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestDoubleClose(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("check-double-close", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.RunWithSuggestedFixes(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/doubleclose")
+}
@@ -0,0 +1,16 @@
+package analyzer
+
+import (
+	"flag"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// NewDeferOnlyAnalyzerWithPackages returns a defer-only analyzer that looks for
+// target types in the given packages instead of the built-in list.
+func NewDeferOnlyAnalyzerWithPackages(packages ...string) *analysis.Analyzer {
+	flags := flag.NewFlagSet("deferOnlyAnalyzer", flag.ExitOnError)
+	analyzer := newDeferOnlyAnalyzer(flags)
+	analyzer.packages = packages
+	return newAnalyzer(analyzer.Run, flags)
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// fieldStores returns the fields of structs that v is stored in
+func fieldStores(v ssa.Value) []*ssa.FieldAddr {
+	fields := []*ssa.FieldAddr{}
+	for _, ref := range *v.Referrers() {
+		store, ok := ref.(*ssa.Store)
+		if !ok || store.Val != v {
+			continue
+		}
+
+		if fieldAddr, ok := store.Addr.(*ssa.FieldAddr); ok {
+			fields = append(fields, fieldAddr)
+		}
+	}
+
+	return fields
+}
+
+// checkFieldClose reports the targets stored in a struct field that no
+// function of the package ever closes
+func (a *deferOnlyAnalyzer) checkFieldClose(rep *reporter, v ssa.Value, funcs []*ssa.Function, targetTypes []any) {
+	for _, field := range fieldStores(v) {
+		structType := field.X.Type().Underlying().(*types.Pointer).Elem()
+		if !a.fieldClosed(structType, field.Field, funcs, targetTypes) {
+			name := structType.Underlying().(*types.Struct).Field(field.Field).Name()
+			rep.reportf(checkFieldClose, v.Type(), field.Pos(), "Rows/Stmt stored in field %s of %s is never closed",
+				name, types.TypeString(structType, types.RelativeTo(rep.pass.Pkg)))
+		}
+	}
+}
+
+// fieldClosed reports whether some function loads the field of structType and closes it
+func (a *deferOnlyAnalyzer) fieldClosed(structType types.Type, field int, funcs []*ssa.Function, targetTypes []any) bool {
+	for _, f := range funcs {
+		for _, b := range f.Blocks {
+			for _, instr := range b.Instrs {
+				switch instr := instr.(type) {
+				case *ssa.FieldAddr:
+					if instr.Field != field || !types.Identical(instr.X.Type().Underlying().(*types.Pointer).Elem(), structType) {
+						continue
+					}
+
+					for _, ref := range *instr.Referrers() {
+						if load, ok := ref.(*ssa.UnOp); ok && a.checkClosed(load, targetTypes) {
+							return true
+						}
+					}
+				case *ssa.Field:
+					if instr.Field == field && types.Identical(instr.X.Type(), structType) && a.checkClosed(instr, targetTypes) {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// closerForgetsField reports whether the struct the target is stored in has a
+// Close method of its own that doesn't close the field, e.g. a result set
+// closing its statement but not its rows. Structs without one, or whose
+// methods are defined in another package, are trusted with the field.
+func (a *deferOnlyAnalyzer) closerForgetsField(field *ssa.FieldAddr, targetTypes []any) bool {
+	ptr, ok := field.X.Type().Underlying().(*types.Pointer)
+	if !ok {
+		return false
+	}
+
+	structType := ptr.Elem()
+	if _, ok := structType.(*types.Named); !ok {
+		return false
+	}
+
+	prog := field.Parent().Prog
+	mset := prog.MethodSets.MethodSet(types.NewPointer(structType))
+	for i := 0; i < mset.Len(); i++ {
+		sel := mset.At(i)
+		if !a.isCloseMethod(sel.Obj().Name()) {
+			continue
+		}
+
+		// The declared method rather than the wrapper of a value receiver
+		method := prog.FuncValue(sel.Obj().(*types.Func))
+		if method == nil || len(method.Blocks) == 0 || a.following[method] {
+			return false
+		}
+
+		a.following[method] = true
+		closed := a.fieldClosed(structType, field.Field, []*ssa.Function{method}, targetTypes)
+		delete(a.following, method)
+
+		return !closed
+	}
+
+	return false
+}
@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// creation is the assignment of a target created by a call
+type creation struct {
+	assign *ast.AssignStmt
+	// name of the variable holding the target
+	name string
+	// after is the statement the defer is inserted after: the error check
+	// following the assignment, or the assignment itself
+	after ast.Stmt
+}
+
+// findCreation returns the assignment of the target created by the call at pos
+func findCreation(file *ast.File, pos token.Pos) (*creation, bool) {
+	path, _ := astutil.PathEnclosingInterval(file, pos, pos)
+	for i, node := range path {
+		if _, ok := node.(*ast.CallExpr); !ok {
+			continue
+		}
+
+		if i+2 >= len(path) {
+			return nil, false
+		}
+
+		assign, ok := path[i+1].(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 || len(assign.Lhs) == 0 {
+			return nil, false
+		}
+
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok || ident.Name == "_" {
+			return nil, false
+		}
+
+		block, ok := path[i+2].(*ast.BlockStmt)
+		if !ok {
+			return nil, false
+		}
+
+		c := &creation{assign: assign, name: ident.Name, after: assign}
+		for j, stmt := range block.List {
+			if stmt == assign && j+1 < len(block.List) && len(assign.Lhs) > 1 && checksErr(block.List[j+1], assign.Lhs[len(assign.Lhs)-1]) {
+				c.after = block.List[j+1]
+			}
+		}
+
+		return c, true
+	}
+
+	return nil, false
+}
+
+// checksErr reports whether stmt is an if statement checking err != nil
+func checksErr(stmt ast.Stmt, err ast.Expr) bool {
+	ifStmt, ok := stmt.(*ast.IfStmt)
+	if !ok || ifStmt.Init != nil {
+		return false
+	}
+
+	cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.NEQ {
+		return false
+	}
+
+	x, ok := cond.X.(*ast.Ident)
+	errIdent, isIdent := err.(*ast.Ident)
+	y, isNil := cond.Y.(*ast.Ident)
+
+	return ok && isIdent && isNil && x.Name == errIdent.Name && y.Name == "nil"
+}
+
+// insertDeferEdit returns an edit inserting a deferred Close of the created
+// target after its error check. It goes at the end of the line, after any
+// trailing comment.
+func insertDeferEdit(fset *token.FileSet, c *creation) analysis.TextEdit {
+	indent := strings.Repeat("\t", fset.Position(c.assign.Pos()).Column-1)
+
+	pos := c.after.End()
+	tokFile := fset.File(pos)
+	if line := tokFile.Line(pos); line < tokFile.LineCount() {
+		pos = tokFile.LineStart(line+1) - 1
+	}
+
+	return analysis.TextEdit{
+		Pos:     pos,
+		End:     pos,
+		NewText: []byte("\n" + indent + "defer " + c.name + ".Close()"),
+	}
+}
+
+// addDeferCloseFix returns a fix adding a deferred Close of the target created
+// by the call at pos
+func addDeferCloseFix(pass *analysis.Pass, pos token.Pos) []analysis.SuggestedFix {
+	file := fileOf(pass, pos)
+	if file == nil {
+		return nil
+	}
+
+	c, ok := findCreation(file, pos)
+	if !ok {
+		return nil
+	}
+
+	return []analysis.SuggestedFix{{
+		Message:   "Add defer " + c.name + ".Close()",
+		TextEdits: []analysis.TextEdit{insertDeferEdit(pass.Fset, c)},
+	}}
+}
+
+// convertToDeferFix returns a fix replacing the Close statement at closePos
+// with a deferred Close after the creation of the target at createdPos
+func convertToDeferFix(pass *analysis.Pass, createdPos, closePos token.Pos) []analysis.SuggestedFix {
+	file := fileOf(pass, createdPos)
+	if file == nil || fileOf(pass, closePos) != file {
+		return nil
+	}
+
+	c, ok := findCreation(file, createdPos)
+	if !ok {
+		return nil
+	}
+
+	path, _ := astutil.PathEnclosingInterval(file, closePos, closePos)
+	var stmt *ast.ExprStmt
+	for _, node := range path {
+		if s, ok := node.(*ast.ExprStmt); ok {
+			stmt = s
+			break
+		}
+	}
+
+	if stmt == nil || stmt.Pos() <= c.after.End() {
+		return nil
+	}
+
+	call, ok := stmt.X.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	// A function closing the target, e.g. Close(rows), is left as is
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != c.name {
+		return nil
+	}
+
+	return []analysis.SuggestedFix{{
+		Message: "Defer " + c.name + ".Close()",
+		TextEdits: []analysis.TextEdit{
+			insertDeferEdit(pass.Fset, c),
+			{Pos: stmt.Pos(), End: stmt.End()},
+		},
+	}}
+}
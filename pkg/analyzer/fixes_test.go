@@ -0,0 +1,17 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestSuggestedFixes(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+
+	analysistest.RunWithSuggestedFixes(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/fixes")
+}
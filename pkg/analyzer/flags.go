@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// stringsFlag is a repeatable flag accepting comma-separated values
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			*s = append(*s, v)
+		}
+	}
+
+	return nil
+}
+
+func (s *stringsFlag) contains(value string) bool {
+	for _, v := range *s {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// choiceFlag is a flag accepting one of a fixed set of values
+type choiceFlag struct {
+	value   string
+	choices []string
+}
+
+func (c *choiceFlag) String() string {
+	return c.value
+}
+
+func (c *choiceFlag) Set(value string) error {
+	for _, choice := range c.choices {
+		if value == choice {
+			c.value = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid value %q, expected one of %s", value, strings.Join(c.choices, ", "))
+}
+
+// negatedFlag is a boolean flag setting the negation of value, e.g. a
+// deprecated flag replaced by its opposite
+type negatedFlag struct {
+	value *bool
+}
+
+func (n negatedFlag) IsBoolFlag() bool { return true }
+
+func (n negatedFlag) String() string {
+	if n.value == nil {
+		return "false"
+	}
+
+	return strconv.FormatBool(!*n.value)
+}
+
+func (n negatedFlag) Set(value string) error {
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+
+	*n.value = !v
+	return nil
+}
@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// leakFlow returns the flow of a leaked target as related information: where
+// it was opened, the functions it was passed to or wrapped by on the way, and
+// the return where the analysis concluded it leaks
+func (a *deferOnlyAnalyzer) leakFlow(target targetValue, targetTypes []any) []analysis.RelatedInformation {
+	related := []analysis.RelatedInformation{{Pos: target.instr.Pos(), Message: "opened here"}}
+	related = append(related, a.passedFlow(*target.value, targetTypes, map[ssa.Value]bool{})...)
+
+	if ret, ok := a.leakingReturn(target, targetTypes); ok && ret != nil {
+		if pos := returnPos(ret); pos.IsValid() {
+			related = append(related, analysis.RelatedInformation{Pos: pos, Message: "leaks at this return"})
+		}
+	}
+
+	return related
+}
+
+// passedFlow returns the calls v is passed to, following the wrappers handing
+// it back through their result
+func (a *deferOnlyAnalyzer) passedFlow(v ssa.Value, targetTypes []any, visited map[ssa.Value]bool) []analysis.RelatedInformation {
+	if visited[v] {
+		return nil
+	}
+	visited[v] = true
+
+	related := []analysis.RelatedInformation{}
+	for _, ref := range *v.Referrers() {
+		call, ok := ref.(*ssa.Call)
+		if !ok || call.Call.IsInvoke() {
+			continue
+		}
+
+		callee := call.Call.StaticCallee()
+		if callee == nil || callee.Signature.Recv() != nil && len(call.Call.Args) > 0 && call.Call.Args[0] == v {
+			// Methods of the target itself, e.g. rows.Next, don't move it
+			continue
+		}
+
+		i := argIndex(call.Call.Args, v)
+		if i < 0 {
+			continue
+		}
+
+		if returnsParam(funcBody(callee), i) {
+			related = append(related, analysis.RelatedInformation{
+				Pos:     call.Pos(),
+				Message: fmt.Sprintf("wrapped by %s here", callee.Name()),
+			})
+			related = append(related, a.passedFlow(call, targetTypes, visited)...)
+			continue
+		}
+
+		related = append(related, analysis.RelatedInformation{
+			Pos:     call.Pos(),
+			Message: fmt.Sprintf("passed to %s here, which doesn't close it", callee.Name()),
+		})
+	}
+
+	return related
+}
+
+// callerFlow returns the calls of a function returning a target that don't
+// close it, for the verify returned-policy
+func callerFlow(fn *ssa.Function, callers map[*ssa.Function][]*ssa.Call) []analysis.RelatedInformation {
+	related := []analysis.RelatedInformation{}
+	for _, call := range callers[fn] {
+		related = append(related, analysis.RelatedInformation{
+			Pos:     call.Pos(),
+			Message: fmt.Sprintf("returned to %s here, which doesn't close it", call.Parent().Name()),
+		})
+	}
+
+	return related
+}
+
+// returnPos returns the position of ret, the closing brace of the function
+// for the implicit return at its end
+func returnPos(ret *ssa.Return) token.Pos {
+	if ret.Pos().IsValid() {
+		return ret.Pos()
+	}
+
+	switch syntax := ret.Parent().Syntax().(type) {
+	case *ast.FuncDecl:
+		return syntax.Body.Rbrace
+	case *ast.FuncLit:
+		return syntax.Body.Rbrace
+	}
+
+	return token.NoPos
+}
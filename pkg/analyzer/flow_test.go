@@ -0,0 +1,64 @@
+package analyzer_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestLeakFlow(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("returned-policy", "verify"); err != nil {
+		t.Fatal(err)
+	}
+
+	results := analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/flow")
+	if len(results) != 1 {
+		t.Fatalf("expected one result, got %d", len(results))
+	}
+
+	result, ok := results[0].Result.(*analyzer.Result)
+	if !ok {
+		t.Fatalf("unexpected result %T", results[0].Result)
+	}
+
+	fset := results[0].Pass.Fset
+	expected := map[int][]string{
+		21: {
+			"21: opened here",
+			"26: passed to logRows here, which doesn't close it",
+			"27: wrapped by wrapRows here",
+			"31: leaks at this return",
+		},
+		39: {
+			"43: returned to iterateOpened here, which doesn't close it",
+		},
+		43: {
+			"43: opened here",
+			"50: leaks at this return",
+		},
+		53: {
+			"53: opened here",
+			"63: leaks at this return",
+		},
+		59: {},
+	}
+
+	for _, d := range result.Diagnostics {
+		line := fset.Position(d.Pos).Line
+		related := []string{}
+		for _, r := range d.Related {
+			related = append(related, fmt.Sprintf("%d: %s", fset.Position(r.Pos).Line, r.Message))
+		}
+
+		if !reflect.DeepEqual(related, expected[line]) {
+			t.Errorf("line %d: expected related %q, got %q", line, expected[line], related)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"go/ast"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// generatedComment is the convention for generated files, see https://go.dev/s/generatedcode
+var generatedComment = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// generator returns the generator named by the generated code comment that
+// precedes the package clause of file, e.g. sqlc for "// Code generated by
+// sqlc. DO NOT EDIT.". Generated files without a name have an empty one.
+func generator(file *ast.File) (string, bool) {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			return "", false
+		}
+
+		for _, comment := range group.List {
+			if !generatedComment.MatchString(comment.Text) {
+				continue
+			}
+
+			fields := strings.Fields(strings.TrimPrefix(comment.Text, "// Code generated "))
+			if len(fields) < 2 || fields[0] != "by" {
+				return "", true
+			}
+
+			return strings.TrimRight(fields[1], ".,:;"), true
+		}
+	}
+
+	return "", false
+}
+
+// skippedGenerated returns the names of the generated files of the pass to skip,
+// all of them with all set, otherwise those of the generators
+func skippedGenerated(pass *analysis.Pass, all bool, generators []string) map[string]bool {
+	files := map[string]bool{}
+	for _, file := range pass.Files {
+		name, ok := generator(file)
+		if !ok {
+			continue
+		}
+
+		if all || matchesGenerator(name, generators) {
+			files[pass.Fset.File(file.Pos()).Name()] = true
+		}
+	}
+
+	return files
+}
+
+// matchesGenerator reports whether the generator name starts with one of the
+// generators, ignoring case, so protoc matches protoc-gen-go
+func matchesGenerator(name string, generators []string) bool {
+	name = strings.ToLower(name)
+	for _, g := range generators {
+		if g != "" && strings.HasPrefix(name, strings.ToLower(g)) {
+			return true
+		}
+	}
+
+	return false
+}
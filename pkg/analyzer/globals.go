@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"go/token"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// findGlobalLoads returns the loads of each package global in funcs. Globals
+// don't track their referrers, a target stored in one is followed through
+// these loads.
+func findGlobalLoads(funcs []*ssa.Function) map[*ssa.Global][]*ssa.UnOp {
+	loads := map[*ssa.Global][]*ssa.UnOp{}
+	for _, f := range funcs {
+		for _, b := range f.Blocks {
+			for _, instr := range b.Instrs {
+				load, ok := instr.(*ssa.UnOp)
+				if !ok || load.Op != token.MUL {
+					continue
+				}
+
+				if g, ok := load.X.(*ssa.Global); ok {
+					loads[g] = append(loads[g], load)
+				}
+			}
+		}
+	}
+
+	return loads
+}
+
+// globalClosed reports whether the target stored in g is closed through a
+// load of g in any function of the package
+func (a *deferOnlyAnalyzer) globalClosed(g *ssa.Global, targetTypes []any) bool {
+	for _, load := range a.globalLoads[g] {
+		if a.checkClosed(load, targetTypes) {
+			return true
+		}
+	}
+
+	return false
+}
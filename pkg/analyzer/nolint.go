@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"go/ast"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// ignoreDirective suppresses the findings of its line, or of the next one
+// when it stands on a line of its own
+const ignoreDirective = "//sqlclosecheck:ignore"
+
+// nolintComment is the golangci-lint directive, //nolint alone suppresses every linter
+var nolintComment = regexp.MustCompile(`^//\s*nolint(?::([\w,-]+))?(?:\s|$)`)
+
+// suppresses reports whether the comment text suppresses the findings of sqlclosecheck
+func suppresses(text string) bool {
+	if text == ignoreDirective || strings.HasPrefix(text, ignoreDirective+" ") {
+		return true
+	}
+
+	m := nolintComment.FindStringSubmatch(text)
+	if m == nil {
+		return false
+	}
+
+	if m[1] == "" {
+		return true
+	}
+
+	for _, linter := range strings.Split(m[1], ",") {
+		if linter == "sqlclosecheck" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// suppressedLines returns, per file name, the lines whose findings are
+// suppressed by a //nolint:sqlclosecheck or //sqlclosecheck:ignore comment, on
+// the line of the comment or below it
+func suppressedLines(pass *analysis.Pass) map[string]map[int]bool {
+	files := map[string]map[int]bool{}
+	for _, file := range pass.Files {
+		var codeLines map[int]bool
+		lines := map[int]bool{}
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				if !suppresses(comment.Text) {
+					continue
+				}
+
+				// The lines with code are only needed once a directive is found
+				if codeLines == nil {
+					codeLines = linesWithCode(pass, file)
+				}
+
+				line := pass.Fset.Position(comment.Pos()).Line
+				lines[line] = true
+				if !codeLines[line] {
+					lines[line+1] = true
+				}
+			}
+		}
+
+		if len(lines) != 0 {
+			files[pass.Fset.File(file.Pos()).Name()] = lines
+		}
+	}
+
+	return files
+}
+
+// linesWithCode returns the lines of file on which a node ends, a comment on
+// another line stands on a line of its own
+func linesWithCode(pass *analysis.Pass, file *ast.File) map[int]bool {
+	lines := map[int]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil, *ast.Comment, *ast.CommentGroup:
+			return false
+		}
+
+		lines[pass.Fset.Position(n.End()-1).Line] = true
+		return true
+	})
+
+	return lines
+}
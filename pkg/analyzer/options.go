@@ -0,0 +1,37 @@
+package analyzer
+
+import (
+	"flag"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Options configure an analyzer from Go code, e.g. when it is embedded in a
+// multichecker, instead of its flags. The zero value is the default analyzer.
+type Options struct {
+	// Packages are checked in addition to the built-in ones, like -packages
+	Packages []string
+	// TargetTypes are fully qualified types checked in addition to Rows/Stmt/NamedStmt, like -target-type
+	TargetTypes []string
+	// CloseMethods close a target in addition to Close, like -close-methods
+	CloseMethods []string
+	// Debug traces the verdict on every target to stderr, like -debug-targets
+	Debug bool
+}
+
+// NewAnalyzerWithOptions returns a defer-only analyzer configured with opts.
+// Its flags start out with the values of opts, flags set later add to them.
+func NewAnalyzerWithOptions(opts Options) *analysis.Analyzer {
+	flags := flag.NewFlagSet("analyzer", flag.ExitOnError)
+	analyzer := newDeferOnlyAnalyzer(flags)
+	analyzer.apply(opts)
+	return newAnalyzer(analyzer.Run, flags)
+}
+
+// apply sets the options on the analyzer
+func (a *deferOnlyAnalyzer) apply(opts Options) {
+	a.extraPackages = append(a.extraPackages, opts.Packages...)
+	a.targetTypeNames = append(a.targetTypeNames, opts.TargetTypes...)
+	a.closeMethods = append(a.closeMethods, opts.CloseMethods...)
+	a.debug = opts.Debug
+}
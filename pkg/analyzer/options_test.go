@@ -0,0 +1,41 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestNewAnalyzerWithOptions(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+
+	checker := analyzer.NewAnalyzerWithOptions(analyzer.Options{
+		Packages: []string{"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/packages/driver"},
+	})
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/packages")
+
+	checker = analyzer.NewAnalyzerWithOptions(analyzer.Options{
+		TargetTypes: []string{
+			"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/closemethods.Conn",
+			"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/closemethods.Pool",
+		},
+		CloseMethods: []string{"Release", "Shutdown"},
+	})
+	analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/closemethods")
+}
+
+func TestNewAnalyzerWithOptionsFlags(t *testing.T) {
+	t.Parallel()
+
+	checker := analyzer.NewAnalyzerWithOptions(analyzer.Options{CloseMethods: []string{"Release"}})
+	if err := checker.Flags.Set("close-methods", "Shutdown"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := checker.Flags.Lookup("close-methods").Value.String(); got != "Release,Shutdown" {
+		t.Errorf("expected the flag to add to the options, got %q", got)
+	}
+}
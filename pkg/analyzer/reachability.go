@@ -0,0 +1,386 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// noReturnFuncs never return to their caller, paths through them can't leak
+var noReturnFuncs = map[string]bool{
+	"log.Fatal":                 true,
+	"log.Fatalf":                true,
+	"log.Fatalln":               true,
+	"log.Panic":                 true,
+	"log.Panicf":                true,
+	"log.Panicln":               true,
+	"(*log.Logger).Fatal":       true,
+	"(*log.Logger).Fatalf":      true,
+	"(*log.Logger).Fatalln":     true,
+	"(*log.Logger).Panic":       true,
+	"(*log.Logger).Panicf":      true,
+	"(*log.Logger).Panicln":     true,
+	"os.Exit":                   true,
+	"runtime.Goexit":            true,
+	"(*testing.common).Fatal":   true,
+	"(*testing.common).Fatalf":  true,
+	"(*testing.common).FailNow": true,
+	"(*testing.common).Skip":    true,
+	"(*testing.common).Skipf":   true,
+	"(*testing.common).SkipNow": true,
+}
+
+// leaksOnSomePath reports whether a path leads from the creation of the target
+// to a return of the function without reaching one of the closing
+// instructions. Paths where the query failed, or the target is nil, are
+// skipped, as are paths that end in a panic or a call that never returns.
+func (a *deferOnlyAnalyzer) leaksOnSomePath(target targetValue, targetTypes []any) bool {
+	_, ok := a.leakingReturn(target, targetTypes)
+	return ok
+}
+
+// leakingReturn returns the return reached by a path on which the target leaks,
+// or nil when the path loops back to the creation, overwriting the target with
+// the one of the next iteration before it is closed
+func (a *deferOnlyAnalyzer) leakingReturn(target targetValue, targetTypes []any) (*ssa.Return, bool) {
+	handling := a.handlingInstrs(*target.value, targetTypes)
+	guards := nilGuards(target)
+
+	start := target.instr.Block()
+	from := 0
+	for i, instr := range start.Instrs {
+		if instr == target.instr {
+			from = i + 1
+			break
+		}
+	}
+
+	visited := map[*ssa.BasicBlock]bool{}
+	var leak *ssa.Return
+	var walk func(b, pred *ssa.BasicBlock, from int) bool
+	walk = func(b, pred *ssa.BasicBlock, from int) bool {
+		for _, instr := range b.Instrs[from:] {
+			if instr == target.instr {
+				leak = nil
+				return overwritten(target.instr, pred)
+			}
+
+			if handling[instr] || isNoReturn(instr) {
+				return false
+			}
+		}
+
+		succs := b.Succs
+		switch last := b.Instrs[len(b.Instrs)-1].(type) {
+		case *ssa.Return:
+			leak = last
+			return true
+		case *ssa.Panic:
+			return false
+		case *ssa.If:
+			if skip, ok := guards.skippedSucc(last); ok {
+				succs = []*ssa.BasicBlock{b.Succs[1-skip]}
+			}
+		}
+
+		for _, succ := range succs {
+			if visited[succ] {
+				continue
+			}
+			visited[succ] = true
+
+			if walk(succ, b, 0) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if !walk(start, nil, from) {
+		return nil, false
+	}
+
+	return leak, true
+}
+
+// overwritten reports whether looping back from pred to the creation of a
+// target replaces it. A phi merging the target keeps it along the edges that
+// carry the phi itself, e.g. an iteration that doesn't assign the variable.
+func overwritten(creation ssa.Instruction, pred *ssa.BasicBlock) bool {
+	phi, ok := creation.(*ssa.Phi)
+	if !ok {
+		return true
+	}
+
+	for i, p := range phi.Block().Preds {
+		if p == pred {
+			return !carries(phi.Edges[i], phi, map[ssa.Value]bool{})
+		}
+	}
+
+	return true
+}
+
+// carries reports whether v may be the phi itself, directly or through the
+// phis merging it, e.g. a variable assigned only on the first iteration
+func carries(v ssa.Value, phi *ssa.Phi, visited map[ssa.Value]bool) bool {
+	if v == phi {
+		return true
+	}
+
+	edgePhi, ok := v.(*ssa.Phi)
+	if !ok || visited[edgePhi] {
+		return false
+	}
+	visited[edgePhi] = true
+
+	for _, edge := range edgePhi.Edges {
+		if carries(edge, phi, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unreachable reports whether no path from the entry of its function leads to
+// the instruction, e.g. a close following a return, so it never runs
+func (a *deferOnlyAnalyzer) unreachable(instr ssa.Instruction) bool {
+	block := instr.Block()
+	if block == nil || block.Parent() == nil {
+		return false
+	}
+
+	fn := block.Parent()
+	blocks, ok := a.reachable[fn]
+	if !ok {
+		blocks = reachableBlocks(fn)
+		if a.reachable != nil {
+			a.reachable[fn] = blocks
+		}
+	}
+
+	return !blocks[block]
+}
+
+// reachableBlocks returns the blocks of fn reachable from its entry, along
+// with the recover block run by a panic
+func reachableBlocks(fn *ssa.Function) map[*ssa.BasicBlock]bool {
+	reachable := map[*ssa.BasicBlock]bool{}
+	if len(fn.Blocks) == 0 {
+		return reachable
+	}
+
+	worklist := []*ssa.BasicBlock{fn.Blocks[0]}
+	if fn.Recover != nil {
+		worklist = append(worklist, fn.Recover)
+	}
+	for len(worklist) > 0 {
+		block := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+		if reachable[block] {
+			continue
+		}
+		reachable[block] = true
+		worklist = append(worklist, block.Succs...)
+	}
+
+	return reachable
+}
+
+func isNoReturn(instr ssa.Instruction) bool {
+	call, ok := instr.(*ssa.Call)
+	if !ok {
+		return false
+	}
+
+	callee := call.Call.StaticCallee()
+	return callee != nil && noReturnFuncs[callee.String()]
+}
+
+// guardValues holds the values whose comparison against nil tells whether the
+// target exists on a branch: the error returned with it, and the target. The
+// ok of an assertion with comma ok tells it by itself.
+type guardValues struct {
+	errs    map[ssa.Value]bool
+	targets map[ssa.Value]bool
+	oks     map[ssa.Value]bool
+}
+
+func nilGuards(target targetValue) guardValues {
+	guards := guardValues{
+		errs:    map[ssa.Value]bool{},
+		targets: map[ssa.Value]bool{*target.value: true},
+		oks:     map[ssa.Value]bool{},
+	}
+	mergingPhis(*target.value, guards)
+
+	if phi, ok := target.instr.(*ssa.Phi); ok {
+		mergedErrs(phi, guards.errs)
+		return guards
+	}
+
+	// The ok of an assertion with comma ok tells whether the target exists
+	if assert, ok := target.instr.(*ssa.TypeAssert); ok {
+		for _, ref := range *assert.Referrers() {
+			if extract, ok := ref.(*ssa.Extract); ok && extract.Index == 1 {
+				guards.oks[extract] = true
+			}
+		}
+		return guards
+	}
+
+	call, ok := target.instr.(*ssa.Call)
+	if !ok {
+		return guards
+	}
+
+	errType := types.Universe.Lookup("error").Type()
+	for _, ref := range *call.Referrers() {
+		extract, ok := ref.(*ssa.Extract)
+		if !ok || !types.Identical(extract.Type(), errType) {
+			continue
+		}
+
+		guards.errs[extract] = true
+
+		// The error may be stored to a variable captured by a closure or a named result
+		for _, eRef := range *extract.Referrers() {
+			if store, ok := eRef.(*ssa.Store); ok {
+				for _, aRef := range *store.Addr.Referrers() {
+					if load, ok := aRef.(*ssa.UnOp); ok && load.Op == token.MUL {
+						guards.errs[load] = true
+					}
+				}
+			}
+		}
+	}
+
+	return guards
+}
+
+// mergingPhis adds the phis merging the target, e.g. in both branches of an if
+// or across the iterations of a loop, along with the errors merged with them.
+// Reached from the creation, comparing such a phi against nil tells whether
+// the target exists.
+func mergingPhis(v ssa.Value, guards guardValues) {
+	worklist := []ssa.Value{v}
+	for len(worklist) > 0 {
+		v := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+
+		for _, ref := range *v.Referrers() {
+			phi, ok := ref.(*ssa.Phi)
+			if !ok || guards.targets[phi] {
+				continue
+			}
+
+			guards.targets[phi] = true
+			mergedErrs(phi, guards.errs)
+			worklist = append(worklist, phi)
+		}
+	}
+}
+
+// mergedErrs adds the phis merging the errors returned with the edges of a
+// merged target, e.g. rows, err = db.Query(...) in both branches of an if
+func mergedErrs(phi *ssa.Phi, errs map[ssa.Value]bool) {
+	errType := types.Universe.Lookup("error").Type()
+	for _, instr := range phi.Block().Instrs {
+		errPhi, ok := instr.(*ssa.Phi)
+		if !ok || !types.Identical(errPhi.Type(), errType) {
+			continue
+		}
+
+		for i, edge := range errPhi.Edges {
+			errExtract, ok := edge.(*ssa.Extract)
+			if !ok {
+				continue
+			}
+
+			if target, ok := phi.Edges[i].(*ssa.Extract); ok && target.Tuple == errExtract.Tuple {
+				errs[errPhi] = true
+				break
+			}
+		}
+	}
+}
+
+// skippedSucc returns the index of the successor of an if on which the query
+// failed or the target is nil
+func (g guardValues) skippedSucc(instr *ssa.If) (int, bool) {
+	// ok of an assertion, the false branch has no target
+	if g.oks[instr.Cond] {
+		return 1, true
+	}
+	if not, ok := instr.Cond.(*ssa.UnOp); ok && not.Op == token.NOT && g.oks[not.X] {
+		return 0, true
+	}
+
+	cond, ok := instr.Cond.(*ssa.BinOp)
+	if !ok || (cond.Op != token.EQL && cond.Op != token.NEQ) {
+		return 0, false
+	}
+
+	operand := cond.X
+	if isNilConst(operand) {
+		operand = cond.Y
+	} else if !isNilConst(cond.Y) {
+		return 0, false
+	}
+
+	switch {
+	case g.errs[operand]:
+		// err != nil, the true branch has no target
+		if cond.Op == token.NEQ {
+			return 0, true
+		}
+		return 1, true
+	case g.targets[operand]:
+		// rows == nil, the true branch has no target
+		if cond.Op == token.EQL {
+			return 0, true
+		}
+		return 1, true
+	}
+
+	return 0, false
+}
+
+func isNilConst(v ssa.Value) bool {
+	c, ok := v.(*ssa.Const)
+	return ok && c.IsNil()
+}
+
+// inSameLoop reports whether the blocks a and b are part of a loop of their
+// function, each one reachable from the other
+func inSameLoop(a, b *ssa.BasicBlock) bool {
+	if a.Parent() != b.Parent() {
+		return false
+	}
+
+	return reaches(a, b) && reaches(b, a)
+}
+
+// reaches reports whether a path of at least one edge leads from a to b
+func reaches(a, b *ssa.BasicBlock) bool {
+	visited := map[*ssa.BasicBlock]bool{}
+	queue := append([]*ssa.BasicBlock{}, a.Succs...)
+	for len(queue) > 0 {
+		block := queue[0]
+		queue = queue[1:]
+		if block == b {
+			return true
+		}
+
+		if visited[block] {
+			continue
+		}
+		visited[block] = true
+		queue = append(queue, block.Succs...)
+	}
+
+	return false
+}
@@ -0,0 +1,213 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Severity tells how serious a diagnostic is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Result is returned by every pass and describes the diagnostics it reported
+type Result struct {
+	Diagnostics []Diagnostic
+	Stats       Stats
+}
+
+// Stats counts what a pass analyzed
+type Stats struct {
+	// Functions is the number of source functions analyzed
+	Functions int
+	// Targets is the number of Rows/Stmt/NamedStmt values checked
+	Targets int
+	// MaxDepth is the deepest recursion following a target through its referrers
+	MaxDepth int
+	// TypeComparisons is the number of comparisons of types with the target
+	// types, each type is compared once per pass
+	TypeComparisons int
+}
+
+// Diagnostic is a reported analysis.Diagnostic along with the target type it
+// is about and its severity
+type Diagnostic struct {
+	analysis.Diagnostic
+	// Check is the name of the check reporting the diagnostic
+	Check string
+	// Type of the target, e.g. database/sql.Rows
+	Type     string
+	Severity Severity
+}
+
+// Lookup returns the recorded diagnostic reported at pos with message
+func (r *Result) Lookup(pos token.Pos, message string) (Diagnostic, bool) {
+	for _, d := range r.Diagnostics {
+		if d.Pos == pos && d.Message == message {
+			return d, true
+		}
+	}
+
+	return Diagnostic{}, false
+}
+
+var resultType = reflect.TypeOf((*Result)(nil))
+
+// severityFlag maps target types, as pkgpath:TypeName, to a severity
+type severityFlag map[string]Severity
+
+func (s severityFlag) String() string {
+	values := make([]string, 0, len(s))
+	for typeName, severity := range s {
+		values = append(values, typeName+":"+string(severity))
+	}
+	sort.Strings(values)
+
+	return strings.Join(values, ",")
+}
+
+func (s severityFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		idx := strings.LastIndex(v, ":")
+		if idx <= 0 || strings.LastIndex(v[:idx], ":") <= 0 {
+			return fmt.Errorf("invalid severity %q, expected pkgpath:Type:severity", v)
+		}
+
+		severity := Severity(v[idx+1:])
+		switch severity {
+		case SeverityError, SeverityWarning, SeverityInfo:
+		default:
+			return fmt.Errorf("invalid severity %q in %q, expected error, warning or info", severity, v)
+		}
+
+		s[v[:idx]] = severity
+	}
+
+	return nil
+}
+
+// reporter reports the diagnostics of a pass and records them in its result
+type reporter struct {
+	pass       *analysis.Pass
+	severities severityFlag
+	changed    changedLines
+	// generated files are skipped when set
+	generated map[string]bool
+	// suppressed are the lines of the files with a nolint comment
+	suppressed map[string]map[int]bool
+	// reported are the positions already reported by each check, a creation
+	// site reached through several targets, e.g. the results of one call, is
+	// reported once
+	reported map[string]map[token.Pos]bool
+	result   *Result
+}
+
+func (a *deferOnlyAnalyzer) newReporter(pass *analysis.Pass) *reporter {
+	rep := &reporter{
+		pass:       pass,
+		severities: a.severities,
+		changed:    a.changed,
+		suppressed: suppressedLines(pass),
+		reported:   map[string]map[token.Pos]bool{},
+		result:     &Result{},
+	}
+
+	// Generated files are skipped unless included, then only those of the excluded generators
+	if !a.includeGenerated || len(a.excludeGeneratedBy) > 0 {
+		rep.generated = skippedGenerated(pass, !a.includeGenerated, a.excludeGeneratedBy)
+	}
+
+	return rep
+}
+
+// report emits d as a diagnostic of the check about the target type
+func (r *reporter) report(check string, target types.Type, d analysis.Diagnostic) {
+	severity, ok := r.severities[targetTypeName(target)]
+	if !ok {
+		severity = SeverityError
+	}
+
+	r.reportSeverity(check, severity, target, d)
+}
+
+// reportSeverity emits d with the severity, regardless of the one configured for the target type
+func (r *reporter) reportSeverity(check string, severity Severity, target types.Type, d analysis.Diagnostic) {
+	// Findings outside of the changed lines are dropped
+	posn := r.pass.Fset.Position(d.Pos)
+	if !r.changed.contains(posn.Filename, posn.Line) {
+		return
+	}
+
+	if r.generated[posn.Filename] || r.suppressed[posn.Filename][posn.Line] {
+		return
+	}
+
+	if r.reported[check][d.Pos] {
+		return
+	}
+	if r.reported[check] == nil {
+		r.reported[check] = map[token.Pos]bool{}
+	}
+	r.reported[check][d.Pos] = true
+
+	if d.Category == "" {
+		d.Category = checkCategory(check)
+	}
+
+	r.pass.Report(d)
+	r.result.Diagnostics = append(r.result.Diagnostics, Diagnostic{
+		Diagnostic: d,
+		Check:      check,
+		Type:       qualifiedTypeName(target),
+		Severity:   severity,
+	})
+}
+
+func (r *reporter) reportf(check string, target types.Type, pos token.Pos, format string, args ...interface{}) {
+	r.report(check, target, analysis.Diagnostic{
+		Pos:     pos,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *reporter) reportfSeverity(check string, severity Severity, target types.Type, pos token.Pos, format string, args ...interface{}) {
+	r.reportSeverity(check, severity, target, analysis.Diagnostic{
+		Pos:     pos,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// targetTypeName returns the pkgpath:TypeName of the named type behind t
+func targetTypeName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return t.String()
+	}
+
+	return named.Obj().Pkg().Path() + ":" + named.Obj().Name()
+}
+
+// qualifiedTypeName returns the pkgpath.TypeName of the named type behind t,
+// e.g. database/sql.Rows
+func qualifiedTypeName(t types.Type) string {
+	return strings.Replace(targetTypeName(t), ":", ".", 1)
+}
@@ -0,0 +1,236 @@
+package analyzer
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// Policies for targets returned to the caller
+const (
+	returnedTrust  = "trust"
+	returnedWarn   = "warn"
+	returnedVerify = "verify"
+)
+
+// findCallers maps the functions to the static calls of them
+func findCallers(funcs []*ssa.Function) map[*ssa.Function][]*ssa.Call {
+	callers := map[*ssa.Function][]*ssa.Call{}
+	for _, f := range funcs {
+		for _, b := range f.Blocks {
+			for _, instr := range b.Instrs {
+				call, ok := instr.(*ssa.Call)
+				if !ok {
+					continue
+				}
+
+				if callee := call.Call.StaticCallee(); callee != nil {
+					callers[callee] = append(callers[callee], call)
+				}
+			}
+		}
+	}
+
+	return callers
+}
+
+// checkReturned applies the returned policy to the returns of v
+func (a *deferOnlyAnalyzer) checkReturned(rep *reporter, v ssa.Value, targetTypes []any, callers map[*ssa.Function][]*ssa.Call) {
+	if a.returnedPolicy.value == returnedTrust {
+		return
+	}
+
+	for _, ref := range *v.Referrers() {
+		ret, ok := ref.(*ssa.Return)
+		if !ok || returnedWithError(ret) {
+			continue
+		}
+
+		for i, result := range ret.Results {
+			if result != v {
+				continue
+			}
+
+			switch a.returnedPolicy.value {
+			case returnedWarn:
+				rep.reportfSeverity(checkUnclosed, SeverityInfo, v.Type(), ret.Pos(), "%s is returned, the caller must close it",
+					qualifiedTypeName(v.Type()))
+			case returnedVerify:
+				if !a.callerCloses(ret.Parent(), i, len(ret.Results), targetTypes, callers) {
+					rep.report(checkUnclosed, v.Type(), analysis.Diagnostic{
+						Pos:     ret.Pos(),
+						Message: qualifiedTypeName(v.Type()) + " is returned but no caller closes it",
+						Related: callerFlow(ret.Parent(), callers),
+					})
+				}
+			}
+		}
+	}
+}
+
+// returnedWithError reports whether ret returns an error that is never nil,
+// e.g. fmt.Errorf("query: %w", err). The callers bail out on the error
+// without closing the targets returned along with it.
+func returnedWithError(ret *ssa.Return) bool {
+	for _, result := range ret.Results {
+		if isErrorType(result.Type()) && nonNilError(result, map[*ssa.Function]bool{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nonNilError reports whether the error v is never nil: it is created by
+// errors.New or fmt.Errorf, converted from a concrete error type, or returned
+// by a helper of the package, e.g. a wrapper of fmt.Errorf, all of whose
+// returns are such errors
+func nonNilError(v ssa.Value, visited map[*ssa.Function]bool) bool {
+	switch v := v.(type) {
+	case *ssa.MakeInterface:
+		return true
+	case *ssa.Phi:
+		for _, edge := range v.Edges {
+			if !nonNilError(edge, visited) {
+				return false
+			}
+		}
+
+		return len(v.Edges) > 0
+	case *ssa.Call:
+		callee := v.Call.StaticCallee()
+		if callee == nil {
+			return false
+		}
+
+		switch callee.String() {
+		case "errors.New", "fmt.Errorf":
+			return true
+		}
+
+		if visited[callee] || callee.Signature.Results().Len() != 1 {
+			return false
+		}
+		visited[callee] = true
+
+		returned := false
+		for _, b := range callee.Blocks {
+			ret, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return)
+			if !ok {
+				continue
+			}
+
+			if !nonNilError(ret.Results[0], visited) {
+				return false
+			}
+			returned = true
+		}
+
+		return returned
+	}
+
+	return false
+}
+
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// handedToCaller reports whether v is returned to the caller, other than along
+// with an error
+func handedToCaller(v ssa.Value) bool {
+	for _, ref := range *v.Referrers() {
+		if ret, ok := ref.(*ssa.Return); ok && !returnedWithError(ret) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// callerCloses reports whether some caller of fn closes its result at index
+func (a *deferOnlyAnalyzer) callerCloses(fn *ssa.Function, index, numResults int, targetTypes []any, callers map[*ssa.Function][]*ssa.Call) bool {
+	for _, call := range callers[fn] {
+		if numResults == 1 {
+			if a.resultClosed(call, targetTypes) {
+				return true
+			}
+			continue
+		}
+
+		for _, ref := range *call.Referrers() {
+			if extract, ok := ref.(*ssa.Extract); ok && extract.Index == index && a.resultClosed(extract, targetTypes) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// resultClosed reports whether the caller closes v, the result it got. A
+// result wrapped in a struct of the caller is closed by a method of the
+// wrapper closing its field, e.g. a deferred it.Close(), instead of being
+// trusted like a target stored in any struct field.
+func (a *deferOnlyAnalyzer) resultClosed(v ssa.Value, targetTypes []any) bool {
+	wrapped := false
+	for _, field := range fieldStores(v) {
+		wrapper, ok := field.X.(*ssa.Alloc)
+		if !ok {
+			continue
+		}
+
+		wrapped = true
+		if a.storedFieldClosed(v, targetTypes) || a.wrapperClosed(wrapper, field.Field, targetTypes) {
+			return true
+		}
+	}
+
+	if wrapped {
+		return false
+	}
+
+	return a.checkClosed(v, targetTypes)
+}
+
+// wrapperClosed reports whether the wrapper is handed over, or one of its
+// methods called on it closes its field
+func (a *deferOnlyAnalyzer) wrapperClosed(wrapper *ssa.Alloc, field int, targetTypes []any) bool {
+	for _, ref := range *wrapper.Referrers() {
+		if _, ok := ref.(*ssa.Return); ok {
+			return true
+		}
+
+		call, ok := callCommon(ref)
+		if !ok || len(call.Args) == 0 || call.Args[0] != wrapper {
+			continue
+		}
+
+		callee := call.StaticCallee()
+		if callee == nil || callee.Signature.Recv() == nil {
+			continue
+		}
+
+		body := funcBody(callee)
+		if len(body.Params) == 0 {
+			continue
+		}
+
+		for _, recvRef := range *body.Params[0].Referrers() {
+			fieldAddr, ok := recvRef.(*ssa.FieldAddr)
+			if !ok || fieldAddr.Field != field {
+				continue
+			}
+
+			for _, fRef := range *fieldAddr.Referrers() {
+				if load, ok := fRef.(*ssa.UnOp); ok && load.Op == token.MUL && a.checkClosed(load, targetTypes) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
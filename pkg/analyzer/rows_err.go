@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"golang.org/x/tools/go/ssa"
+)
+
+const (
+	errMethod = "Err"
+	// sqlRowsName is the concrete Rows whose iteration errors are only returned by Err
+	sqlRowsName = "database/sql:" + rowsName
+)
+
+// checkRowsErr reports the iterations of a database/sql Rows v after which
+// its Err isn't called on any path, dropping the errors that ended the
+// iteration. Rows handed to another function after the iteration may be
+// checked there.
+func (a *deferOnlyAnalyzer) checkRowsErr(rep *reporter, v ssa.Value) {
+	if targetTypeName(v.Type()) != sqlRowsName {
+		return
+	}
+
+	var nexts []*ssa.Call
+	var checks []ssa.Instruction
+	for _, ref := range *v.Referrers() {
+		if call, ok := ref.(*ssa.Call); ok {
+			if _, ok := methodCall(call, v, nextMethod); ok {
+				nexts = append(nexts, call)
+				continue
+			}
+		}
+
+		if _, ok := methodCall(ref, v, errMethod); ok || passedTo(ref, v) {
+			checks = append(checks, ref)
+		}
+	}
+
+	for _, next := range nexts {
+		if !checkedAfter(next, checks) {
+			rep.reportf(checkRowsErr, v.Type(), next.Pos(), "rows.Err() not checked after iteration")
+		}
+	}
+}
+
+// passedTo reports whether instr calls a function, not a method of v, with v
+// as an argument
+func passedTo(instr ssa.Instruction, v ssa.Value) bool {
+	call, ok := callCommon(instr)
+	if !ok || call.IsInvoke() {
+		return false
+	}
+
+	if callee := call.StaticCallee(); callee != nil && callee.Signature.Recv() != nil {
+		return false
+	}
+
+	for _, arg := range call.Args {
+		if arg == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkedAfter reports whether one of checks follows next on some path
+func checkedAfter(next *ssa.Call, checks []ssa.Instruction) bool {
+	for _, check := range checks {
+		if check.Block() == next.Block() && indexOf(check) > indexOf(next) {
+			return true
+		}
+
+		if reaches(next.Block(), check.Block()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indexOf returns the index of instr in its block
+func indexOf(instr ssa.Instruction) int {
+	for i, in := range instr.Block().Instrs {
+		if in == instr {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,52 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestSeverity(t *testing.T) {
+	t.Parallel()
+
+	testdata := analysistest.TestData()
+	checker := analyzer.NewDeferOnlyAnalyzer()
+	if err := checker.Flags.Set("severity", "database/sql:Rows:warning"); err != nil {
+		t.Fatal(err)
+	}
+
+	results := analysistest.Run(t, testdata, checker, "github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/severity")
+	if len(results) != 1 {
+		t.Fatalf("expected one result, got %d", len(results))
+	}
+
+	result, ok := results[0].Result.(*analyzer.Result)
+	if !ok {
+		t.Fatalf("unexpected result %T", results[0].Result)
+	}
+
+	expected := map[string]analyzer.Severity{
+		"database/sql.Rows": analyzer.SeverityWarning,
+		"database/sql.Stmt": analyzer.SeverityError,
+	}
+	if len(result.Diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %v", len(expected), result.Diagnostics)
+	}
+
+	for _, d := range result.Diagnostics {
+		if d.Severity != expected[d.Type] {
+			t.Errorf("expected %s to have severity %q, got %q", d.Type, expected[d.Type], d.Severity)
+		}
+	}
+}
+
+func TestSeverityFlagInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"Rows:warning", "database/sql:Rows:fatal", "database/sql:Rows"} {
+		if err := analyzer.NewDeferOnlyAnalyzer().Flags.Set("severity", value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
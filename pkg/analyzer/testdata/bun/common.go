@@ -0,0 +1,12 @@
+package bun
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+var (
+	ctx context.Context
+	db  *bun.DB
+)
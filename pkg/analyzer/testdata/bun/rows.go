@@ -0,0 +1,47 @@
+package bun
+
+import (
+	"log"
+)
+
+func correctDeferSelectRows() {
+	rows, err := db.NewSelect().Table("users").Column("username").Rows(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func correctDeferQueryContext() {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func missingCloseSelectRows() {
+	rows, err := db.NewSelect().Table("users").Column("username").Rows(ctx) // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
+
+func missingCloseQueryContext() {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,24 @@
+package bun
+
+import (
+	"log"
+)
+
+func correctDeferStmt() {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE id = ?")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stmt.Close()
+
+	_, _ = stmt.ExecContext(ctx, 1)
+}
+
+func missingCloseStmt() {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE id = ?") // want "github.com/uptrace/bun.Stmt was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = stmt.ExecContext(ctx, 1)
+}
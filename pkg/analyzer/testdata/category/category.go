@@ -0,0 +1,28 @@
+package category
+
+import (
+	"context"
+	"database/sql"
+)
+
+func notClosed(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func notDeferred(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	rows.Close() // want "Close should use defer"
+}
@@ -0,0 +1,16 @@
+package changed
+
+import (
+	"context"
+	"database/sql"
+)
+
+func unchangedLeak(ctx context.Context, db *sql.DB) {
+	rows, _ := db.QueryContext(ctx, "SELECT username FROM users")
+	_ = rows
+}
+
+func changedLeak(ctx context.Context, db *sql.DB) {
+	rows, _ := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	_ = rows
+}
@@ -0,0 +1,76 @@
+package cleanup
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// cleanups are run in reverse order by runCleanups
+var cleanups []func()
+
+// registerCleanup is configured as a cleanup func, its funcs are called by runCleanups
+func registerCleanup(f func()) {
+	cleanups = append(cleanups, f)
+}
+
+func runCleanups() {
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+}
+
+// withTestCleanup hands the func over to testing.T
+func withTestCleanup(t *testing.T, f func()) {
+	t.Helper()
+	t.Cleanup(f)
+}
+
+// discard never calls the func passed to it
+func discard(f func()) {}
+
+func closedByRegisteredCleanup(ctx context.Context, db *sql.DB) {
+	defer runCleanups()
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	registerCleanup(func() { rows.Close() })
+
+	for rows.Next() {
+	}
+}
+
+func closedByTestCleanup(t *testing.T, db *sql.DB) {
+	rows, err := db.QueryContext(context.Background(), "SELECT username FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	withTestCleanup(t, func() { rows.Close() })
+
+	for rows.Next() {
+	}
+}
+
+func closedByTestingCleanup(t *testing.T, db *sql.DB) {
+	rows, err := db.QueryContext(context.Background(), "SELECT username FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { rows.Close() })
+
+	for rows.Next() {
+	}
+}
+
+func closureDiscarded(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+	discard(func() { rows.Close() })
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,50 @@
+package cleanup
+
+import (
+	"context"
+	"database/sql"
+)
+
+// deferStack is a manual defer stack, Add is configured as a cleanup func as
+// the funcs it keeps are called by Run
+type deferStack struct {
+	funcs []func()
+}
+
+func (d *deferStack) Add(f func()) {
+	d.funcs = append(d.funcs, f)
+}
+
+func (d *deferStack) Run() {
+	for i := len(d.funcs) - 1; i >= 0; i-- {
+		d.funcs[i]()
+	}
+}
+
+func closedByDeferStack(ctx context.Context, db *sql.DB) {
+	d := &deferStack{}
+	defer d.Run()
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	d.Add(func() { rows.Close() })
+
+	for rows.Next() {
+	}
+}
+
+func deferStackWithoutClose(ctx context.Context, db *sql.DB) {
+	d := &deferStack{}
+	defer d.Run()
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+	d.Add(func() { _ = rows.Err() })
+
+	for rows.Next() {
+	}
+}
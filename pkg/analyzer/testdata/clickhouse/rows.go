@@ -0,0 +1,85 @@
+package clickhouse
+
+import (
+	"context"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+func deferredRowsClose(ctx context.Context, conn driver.Conn) {
+	rows, err := conn.Query(ctx, "SELECT id FROM events")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func rowsNotClosed(ctx context.Context, conn driver.Conn) {
+	rows, err := conn.Query(ctx, "SELECT id FROM events") // want "github.com/ClickHouse/clickhouse-go/v2/lib/driver.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func openedConnRowsNotClosed(ctx context.Context) {
+	conn, err := clickhouse.Open(&clickhouse.Options{Addr: []string{"127.0.0.1:9000"}})
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	rows, err := conn.Query(ctx, "SELECT id FROM events") // want "github.com/ClickHouse/clickhouse-go/v2/lib/driver.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func returnedRows(ctx context.Context, conn clickhouse.Conn) (driver.Rows, error) {
+	return conn.Query(ctx, "SELECT id FROM events")
+}
+
+func callerClosesReturnedRows(ctx context.Context, conn driver.Conn) {
+	rows, err := returnedRows(ctx, conn)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func callerLeaksReturnedRows(ctx context.Context, conn driver.Conn) {
+	rows, err := returnedRows(ctx, conn) // want "github.com/ClickHouse/clickhouse-go/v2/lib/driver.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func closeRows(rows driver.Rows) { // want closeRows:"closesParams\\(\\[0\\]\\)"
+	_ = rows.Close()
+}
+
+func passedRowsClosed(ctx context.Context, conn driver.Conn) {
+	rows, err := conn.Query(ctx, "SELECT id FROM events")
+	if err != nil {
+		return
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+	}
+}
@@ -8,7 +8,7 @@ import (
 // rowsIncorrectNoErr provides an example of incorrect closing by not calling Err()
 func rowsIncorrectNoErr() {
 	age := 40
-	rows, err := db.QueryContext(ctx, "SELECT name FROM users WHERE age=?", age) // want "Rows/Stmt/NamedStmt was not closed"
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users WHERE age=?", age) // want "database/sql.Rows was not closed"
 	if err != nil {
 		log.Fatal(err)
 	}
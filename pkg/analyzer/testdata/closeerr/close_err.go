@@ -0,0 +1,61 @@
+package closeerr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+func insertNamedErr(ctx context.Context, db *sql.DB) (err error) {
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (username) VALUES (?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close() // want "Close error of a Stmt used for writes is dropped"
+
+	_, err = stmt.ExecContext(ctx, "alice")
+	return err
+}
+
+func insertUnnamedErr(ctx context.Context, db *sql.DB) error {
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (username) VALUES (?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close() // want "Close error of a Stmt used for writes is dropped"
+
+	_, err = stmt.ExecContext(ctx, "alice")
+	return err
+}
+
+func insertJoined(ctx context.Context, db *sql.DB) (err error) {
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (username) VALUES (?)")
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, stmt.Close()) }()
+
+	_, err = stmt.ExecContext(ctx, "alice")
+	return err
+}
+
+func selectStmt(ctx context.Context, db *sql.DB) error {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	var username string
+	return stmt.QueryRowContext(ctx, 1).Scan(&username)
+}
+
+func insertNoError(ctx context.Context, db *sql.DB) {
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (username) VALUES (?)")
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	_, _ = stmt.ExecContext(ctx, "alice")
+}
@@ -0,0 +1,18 @@
+package imports
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+func insert(ctx context.Context, db *sql.DB) (err error) {
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (username) VALUES (?)")
+	if err != nil {
+		return fmt.Errorf("preparing: %w", err)
+	}
+	defer stmt.Close() // want "Close error of a Stmt used for writes is dropped"
+
+	_, err = stmt.ExecContext(ctx, "alice")
+	return err
+}
@@ -0,0 +1,82 @@
+package closeerror
+
+import (
+	"context"
+	"database/sql"
+	"log"
+)
+
+func closeChecked(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+	}
+
+	if err := rows.Close(); err != nil { // want "Close should use defer"
+		return err
+	}
+
+	return nil
+}
+
+func closeReturned(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+	}
+
+	return rows.Close() // want "Close should use defer"
+}
+
+func closeIgnored(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+
+	rows.Close() // want "Close should use defer" "error returned by Close is not checked"
+}
+
+func closeDiscardedExplicitly(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+
+	_ = rows.Close() // want "Close should use defer"
+}
+
+func closeDeferred(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func stmtCloseIgnored(ctx context.Context, db *sql.DB) {
+	for _, query := range []string{"DELETE FROM users", "DELETE FROM orders"} {
+		stmt, err := db.PrepareContext(ctx, query)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		_, _ = stmt.ExecContext(ctx)
+		stmt.Close() // want "error returned by Close is not checked"
+	}
+}
@@ -0,0 +1,58 @@
+package closemethods
+
+import "log"
+
+// Conn is returned to its pool by Release
+type Conn struct{}
+
+func (c *Conn) Query(query string) error { return nil }
+
+func (c *Conn) Release() {}
+
+// Pool is closed by Shutdown
+type Pool struct{}
+
+func (p *Pool) Acquire() (*Conn, error) { return &Conn{}, nil }
+
+func (p *Pool) Shutdown() {}
+
+func NewPool() (*Pool, error) { return &Pool{}, nil }
+
+func releasedByDefer(pool *Pool) {
+	conn, err := pool.Acquire()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Release()
+
+	_ = conn.Query("SELECT username FROM users")
+}
+
+func releasedWithoutDefer(pool *Pool) {
+	conn, err := pool.Acquire()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_ = conn.Query("SELECT username FROM users")
+	conn.Release() // want "Close should use defer"
+}
+
+func neverReleased(pool *Pool) {
+	conn, err := pool.Acquire() // want "closemethods.Conn was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_ = conn.Query("SELECT username FROM users")
+}
+
+func shutdownByDefer() {
+	pool, err := NewPool()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer pool.Shutdown()
+
+	releasedByDefer(pool)
+}
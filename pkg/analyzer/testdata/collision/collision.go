@@ -0,0 +1,26 @@
+package collision
+
+import (
+	"log"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/collision/fakesql"
+)
+
+func nonClosableRows() {
+	rows, err := fakesql.Query()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
+
+func missingCloseStmt() {
+	stmt, err := fakesql.Prepare() // want "fakesql.Stmt was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_ = stmt
+}
@@ -0,0 +1,23 @@
+package fakesql
+
+// Rows shares its name with the closable target types but has no Close method.
+type Rows struct{}
+
+func (r *Rows) Next() bool {
+	return false
+}
+
+// Stmt is closable and should still be tracked.
+type Stmt struct{}
+
+func (s *Stmt) Close() error {
+	return nil
+}
+
+func Query() (*Rows, error) {
+	return &Rows{}, nil
+}
+
+func Prepare() (*Stmt, error) {
+	return &Stmt{}, nil
+}
@@ -0,0 +1,31 @@
+package copyclose
+
+import (
+	"log"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/copyclose/driver"
+)
+
+func closeOriginal() {
+	rows, err := driver.Query()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func closeCopy() {
+	rows, err := driver.Query() // want "driver.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	r := *rows
+	defer r.Close() // want "Close is called on a copy and does not close the original Rows/Stmt"
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,19 @@
+package driver
+
+// Rows is a value type, the copies of a Rows share nothing with each other.
+type Rows struct {
+	closed bool
+}
+
+func (r Rows) Next() bool {
+	return !r.closed
+}
+
+func (r Rows) Close() error {
+	r.closed = true
+	return nil
+}
+
+func Query() (*Rows, error) {
+	return &Rows{}, nil
+}
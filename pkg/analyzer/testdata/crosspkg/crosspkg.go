@@ -0,0 +1,34 @@
+package crosspkg
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/crosspkg/dbutil"
+)
+
+func deferredDrain(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer dbutil.Drain(rows)
+}
+
+func deferredClosureDrain(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer func() { _ = dbutil.Drain(rows) }()
+}
+
+func notDrained(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
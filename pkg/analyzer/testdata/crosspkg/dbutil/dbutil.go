@@ -0,0 +1,13 @@
+package dbutil
+
+import (
+	"database/sql"
+)
+
+// Drain consumes the remaining rows and closes them.
+func Drain(rows *sql.Rows) error {
+	for rows.Next() {
+	}
+
+	return rows.Close()
+}
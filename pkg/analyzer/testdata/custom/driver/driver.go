@@ -0,0 +1,14 @@
+package driver
+
+// Rows is closed by a Close method that doesn't return an error.
+type Rows struct{}
+
+func (r *Rows) Next() bool {
+	return false
+}
+
+func (r *Rows) Close() {}
+
+func Query() (*Rows, error) {
+	return &Rows{}, nil
+}
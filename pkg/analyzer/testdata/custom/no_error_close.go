@@ -0,0 +1,53 @@
+package custom
+
+import (
+	"log"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/custom/driver"
+)
+
+func noErrorCloseDeferred() {
+	rows, err := driver.Query()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func noErrorCloseMissing() {
+	rows, err := driver.Query() // want "driver.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
+
+func noErrorCloseNotDeferred() {
+	rows, err := driver.Query()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+
+	rows.Close() // want "Close should use defer"
+}
+
+func noErrorCloseCopy() {
+	rows, err := driver.Query() // want "driver.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	r := *rows
+	defer r.Close() // want "Close is called on a copy and does not close the original Rows/Stmt"
+
+	for rows.Next() {
+	}
+}
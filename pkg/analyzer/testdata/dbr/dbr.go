@@ -0,0 +1,104 @@
+package dbr
+
+import (
+	"context"
+
+	"github.com/gocraft/dbr/v2"
+)
+
+type user struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func deferredRowsClose(ctx context.Context, sess *dbr.Session) {
+	rows, err := sess.Select("id", "name").From("users").RowsContext(ctx)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func rowsNotClosed(sess *dbr.Session) {
+	rows, err := sess.Select("id", "name").From("users").Rows() // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func txRowsNotClosed(tx *dbr.Tx) {
+	rows, err := tx.Select("id").From("users").Rows() // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func deferredIteratorClose(ctx context.Context, sess *dbr.Session) {
+	iter, err := sess.Select("id", "name").From("users").IterateContext(ctx)
+	if err != nil {
+		return
+	}
+	defer iter.Close()
+
+	for iter.Next() {
+		var u user
+		_ = iter.Scan(&u)
+	}
+}
+
+func iteratorNotClosed(sess *dbr.Session) {
+	iter, err := sess.Select("id", "name").From("users").Iterate() // want "github.com/gocraft/dbr/v2.Iterator was not closed"
+	if err != nil {
+		return
+	}
+
+	for iter.Next() {
+		var u user
+		_ = iter.Scan(&u)
+	}
+}
+
+func loadDoesNotLeak(sess *dbr.Session) ([]user, error) {
+	var users []user
+	_, err := sess.Select("id", "name").From("users").Load(&users)
+	return users, err
+}
+
+func sessionIsNotClosed(conn *dbr.Connection) {
+	sess := conn.NewSession(nil)
+
+	var u user
+	_ = sess.Select("id", "name").From("users").LoadOne(&u)
+}
+
+func txRolledBackUnlessCommitted(sess *dbr.Session) error {
+	tx, err := sess.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	if _, err := tx.InsertInto("users").Columns("name").Values("gopher").Exec(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func txNotEnded(sess *dbr.Session) {
+	tx, err := sess.Begin() // want "Tx was neither committed nor rolled back"
+	if err != nil {
+		return
+	}
+
+	_, _ = tx.InsertInto("users").Columns("name").Values("gopher").Exec()
+}
@@ -0,0 +1,36 @@
+package depthlimit
+
+import (
+	"context"
+	"database/sql"
+)
+
+func shallowClosure(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer func() { rows.Close() }()
+
+	for rows.Next() {
+	}
+}
+
+func deeplyNestedClosures(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows follow-through stopped at depth 3, assuming it is closed"
+	if err != nil {
+		return
+	}
+	defer func() {
+		func() {
+			func() {
+				func() {
+					rows.Close()
+				}()
+			}()
+		}()
+	}()
+
+	for rows.Next() {
+	}
+}
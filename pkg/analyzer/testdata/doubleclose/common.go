@@ -0,0 +1,11 @@
+package doubleclose
+
+import (
+	"context"
+	"database/sql"
+)
+
+var (
+	ctx context.Context
+	db  *sql.DB
+)
@@ -0,0 +1,111 @@
+package doubleclose
+
+import (
+	"log"
+)
+
+func deferAndClose() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+
+	rows.Close() // want "Rows/Stmt closed more than once"
+}
+
+func closeTwice() {
+	stmt, err := db.PrepareContext(ctx, "SELECT name FROM users WHERE id = ?")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stmt.Close()
+
+	stmt.Close() // want "Rows/Stmt closed more than once"
+	stmt.Close() // want "Rows/Stmt closed more than once"
+}
+
+func closeWithErrCheck() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	if err := rows.Close(); err != nil { // want "Rows/Stmt closed more than once"
+		log.Print(err)
+	}
+}
+
+func closeWithDanglingErrCheck() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	err = rows.Close() // want "Rows/Stmt closed more than once"
+	if err != nil {
+		log.Print(err)
+	}
+}
+
+func closeReturningErr() error {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return rows.Close() // want "Rows/Stmt closed more than once"
+}
+
+func closeBeforeEarlyReturn(skip bool) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if skip {
+		rows.Close() // want "Close should use defer"
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func deferredClosureAndClose() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		rows.Close()
+	}()
+
+	for rows.Next() {
+	}
+	rows.Close() // want "Rows/Stmt closed more than once"
+}
+
+func deferredGuardedClosureAndClose() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if rows != nil {
+			rows.Close()
+		}
+	}()
+
+	for rows.Next() {
+	}
+	rows.Close()
+	rows = nil
+}
@@ -0,0 +1,44 @@
+package fieldclose
+
+import (
+	"context"
+	"database/sql"
+)
+
+type Iterator struct {
+	rows *sql.Rows
+}
+
+func NewIterator(ctx context.Context, db *sql.DB) (*Iterator, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Iterator{rows: rows}, nil
+}
+
+func (it *Iterator) Next() bool {
+	return it.rows.Next()
+}
+
+func (it *Iterator) Close() error {
+	return it.rows.Close()
+}
+
+type LeakingIterator struct {
+	rows *sql.Rows
+}
+
+func NewLeakingIterator(ctx context.Context, db *sql.DB) (*LeakingIterator, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	return &LeakingIterator{rows: rows}, nil // want "Rows/Stmt stored in field rows of LeakingIterator is never closed"
+}
+
+func (it *LeakingIterator) Next() bool {
+	return it.rows.Next()
+}
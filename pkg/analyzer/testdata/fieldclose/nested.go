@@ -0,0 +1,54 @@
+package fieldclose
+
+import (
+	"context"
+	"database/sql"
+)
+
+type cursor struct {
+	rows *sql.Rows
+}
+
+type page struct {
+	cursor cursor
+}
+
+type report struct {
+	page *page
+}
+
+func nestedSelectorDeferredClose(ctx context.Context, db *sql.DB) error {
+	r := report{page: &page{}}
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+	r.page.cursor.rows = rows
+	defer r.page.cursor.rows.Close()
+
+	for r.page.cursor.rows.Next() {
+	}
+
+	return r.page.cursor.rows.Err()
+}
+
+type leakingCursor struct {
+	rows *sql.Rows
+}
+
+type leakingPage struct {
+	cursor leakingCursor
+}
+
+func nestedSelectorNeverClosed(ctx context.Context, db *sql.DB) (*leakingPage, error) {
+	p := &leakingPage{}
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+	p.cursor.rows = rows // want "Rows/Stmt stored in field rows of leakingCursor is never closed"
+
+	return p, nil
+}
@@ -0,0 +1,109 @@
+package fixes
+
+import (
+	"context"
+	"database/sql"
+)
+
+func addDeferClose(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func addDeferCloseWithoutErrCheck(ctx context.Context, db *sql.DB) {
+	stmt, _ := db.PrepareContext(ctx, "SELECT username FROM users") // want "database/sql.Stmt was not closed"
+	_, _ = stmt.ExecContext(ctx)
+}
+
+func convertToDefer(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	rows.Close() // want "Close should use defer"
+}
+
+func convertToDeferLeakingPath(ctx context.Context, db *sql.DB, stop bool) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	if stop {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	rows.Close() // want "Close should use defer"
+}
+
+func bothInOneFunction(ctx context.Context, db *sql.DB) {
+	users, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	orders, err := db.QueryContext(ctx, "SELECT id FROM orders")
+	if err != nil {
+		return
+	}
+
+	for users.Next() {
+	}
+
+	for orders.Next() {
+	}
+
+	orders.Close() // want "Close should use defer"
+}
+
+func convertStmtToDefer(ctx context.Context, db *sql.DB) {
+	stmt, err := db.PrepareContext(ctx, "UPDATE users SET active = false")
+	if err != nil {
+		return
+	}
+
+	_, _ = stmt.ExecContext(ctx)
+
+	stmt.Close() // want "Close should use defer"
+}
+
+func assignedCloseNotRewritten(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	_ = rows.Close() // want "Close should use defer"
+}
+
+// Close closes rows like a method of them
+func Close(rows *sql.Rows) { // want Close:"closesParams\\(\\[0\\]\\)"
+	_ = rows.Close()
+}
+
+func closedByFunctionNotRewritten(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	Close(rows) // want "Close should use defer"
+}
@@ -0,0 +1,64 @@
+package flow
+
+import (
+	"context"
+	"database/sql"
+)
+
+type userRows struct {
+	rows *sql.Rows
+}
+
+func wrapRows(rows *sql.Rows) *userRows {
+	return &userRows{rows: rows}
+}
+
+func logRows(rows *sql.Rows) {
+	_ = rows.Err()
+}
+
+func leakedThroughHelpers(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	logRows(rows)
+	it := wrapRows(rows)
+
+	for it.rows.Next() {
+	}
+}
+
+func openRows(ctx context.Context, db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil // want "database/sql.Rows is returned but no caller closes it"
+}
+
+func iterateOpened(ctx context.Context, db *sql.DB) {
+	rows, err := openRows(ctx, db) // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func closedOnlyOnError(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return err
+	}
+
+	if err := rows.Err(); err != nil {
+		rows.Close() // want "Close should use defer"
+		return err
+	}
+
+	return nil
+}
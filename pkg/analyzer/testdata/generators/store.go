@@ -0,0 +1,11 @@
+package generators
+
+import (
+	"context"
+	"database/sql"
+)
+
+func handwrittenQuery(ctx context.Context, db *sql.DB) {
+	rows, _ := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	_ = rows
+}
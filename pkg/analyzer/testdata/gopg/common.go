@@ -0,0 +1,7 @@
+package gopg
+
+import (
+	"github.com/go-pg/pg/v10"
+)
+
+var db *pg.DB
@@ -0,0 +1,18 @@
+package gopg
+
+func correctDeferConn() {
+	conn := db.Conn()
+	defer conn.Close()
+
+	_, _ = conn.Exec("SET search_path TO tenant")
+}
+
+func missingCloseConn() {
+	conn := db.Conn() // want "github.com/go-pg/pg/v10.Conn was not closed"
+
+	_, _ = conn.Exec("SET search_path TO tenant")
+}
+
+func returnedConn() interface{ Close() error } {
+	return db.Conn()
+}
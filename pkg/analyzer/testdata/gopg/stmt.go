@@ -0,0 +1,34 @@
+package gopg
+
+import (
+	"log"
+)
+
+func correctDeferStmt() {
+	stmt, err := db.Prepare("SELECT username FROM users WHERE id = $1")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stmt.Close()
+
+	_, _ = stmt.Exec(1)
+}
+
+func missingCloseStmt() {
+	stmt, err := db.Prepare("SELECT username FROM users WHERE id = $1") // want "github.com/go-pg/pg/v10.Stmt was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = stmt.Exec(1)
+}
+
+func nonDeferredCloseStmt() {
+	stmt, err := db.Prepare("SELECT username FROM users WHERE id = $1")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = stmt.Exec(1)
+	stmt.Close() // want "Close should use defer"
+}
@@ -0,0 +1,88 @@
+//go:build go1.23
+
+package iterseq
+
+import (
+	"context"
+	"database/sql"
+	"iter"
+)
+
+type User struct {
+	Name string
+}
+
+// Users opens the rows when iterated and closes them when the iteration ends,
+// either exhausted or stopped early by the consumer
+func Users(ctx context.Context, db *sql.DB) iter.Seq2[User, error] {
+	return func(yield func(User, error) bool) {
+		rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+		if err != nil {
+			yield(User{}, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var user User
+			if err := rows.Scan(&user.Name); err != nil {
+				yield(User{}, err)
+				return
+			}
+
+			if !yield(user, nil) {
+				return
+			}
+		}
+	}
+}
+
+// UsersEager opens the rows right away, the returned iterator closes them
+func UsersEager(ctx context.Context, db *sql.DB) (iter.Seq2[User, error], error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(User, error) bool) {
+		defer rows.Close()
+
+		for rows.Next() {
+			var user User
+			if !yield(user, rows.Scan(&user.Name)) {
+				return
+			}
+		}
+	}, nil
+}
+
+// UsersLeakOnEarlyStop only closes the rows once they are exhausted
+func UsersLeakOnEarlyStop(ctx context.Context, db *sql.DB) iter.Seq2[User, error] {
+	return func(yield func(User, error) bool) {
+		rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+		if err != nil {
+			yield(User{}, err)
+			return
+		}
+
+		for rows.Next() {
+			var user User
+			if !yield(user, rows.Scan(&user.Name)) {
+				return
+			}
+		}
+
+		rows.Close() // want "Close should use defer"
+	}
+}
+
+func consume(ctx context.Context, db *sql.DB) error {
+	for user, err := range Users(ctx, db) {
+		if err != nil {
+			return err
+		}
+		_ = user
+	}
+
+	return nil
+}
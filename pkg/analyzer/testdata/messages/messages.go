@@ -0,0 +1,42 @@
+package messages
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func rowsAndStmtNotClosed(ctx context.Context, db *sql.DB) {
+	stmt, err := db.PrepareContext(ctx, "SELECT name FROM users WHERE id = ?") // want `^database/sql\.Stmt was not closed$`
+	if err != nil {
+		return
+	}
+
+	rows, err := stmt.QueryContext(ctx, 1) // want `^database/sql\.Rows was not closed$`
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func connNotClosed(ctx context.Context, db *sql.DB) {
+	conn, err := db.Conn(ctx) // want `^database/sql\.Conn was not closed$`
+	if err != nil {
+		return
+	}
+
+	_ = conn.PingContext(ctx)
+}
+
+func pgxRowsNotClosed(ctx context.Context, conn *pgx.Conn) {
+	rows, err := conn.Query(ctx, "SELECT name FROM users") // want `^github\.com/jackc/pgx/v5\.Rows was not closed$`
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func connect(ctx context.Context, dsn string) (driver.Conn, error) {
+	connector, err := mysql.MySQLDriver{}.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return connector.Connect(ctx)
+}
+
+func deferredRowsClose(conn driver.Conn) error {
+	stmt, err := conn.Prepare("SELECT id FROM users")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(nil)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return rows.Next(make([]driver.Value, len(rows.Columns())))
+}
+
+func rowsNotClosed(conn driver.Conn) error {
+	stmt, err := conn.Prepare("SELECT id FROM users")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Query(nil) // want "database/sql/driver.Rows was not closed"
+	if err != nil {
+		return err
+	}
+
+	return rows.Next(make([]driver.Value, len(rows.Columns())))
+}
+
+func stmtNotClosed(conn driver.Conn) (int, error) {
+	stmt, err := conn.Prepare("SELECT id FROM users") // want "database/sql/driver.Stmt was not closed"
+	if err != nil {
+		return 0, err
+	}
+
+	return stmt.NumInput(), nil
+}
+
+func connNotClosed(dsn string) error {
+	conn, err := mysql.MySQLDriver{}.Open(dsn) // want "database/sql/driver.Conn was not closed"
+	if err != nil {
+		return err
+	}
+
+	stmt, err := conn.Prepare("SELECT 1")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	return nil
+}
+
+func connClosed(ctx context.Context, dsn string) error {
+	conn, err := connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return rowsNotClosed(conn)
+}
@@ -0,0 +1,79 @@
+package nolint
+
+import (
+	"context"
+	"database/sql"
+)
+
+func suppressedOnLine(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") //nolint:sqlclosecheck // closed by the pool
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func suppressedAmongLinters(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") //nolint:errcheck,sqlclosecheck
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func suppressedByBareNolint(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") //nolint
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func suppressedAbove(ctx context.Context, db *sql.DB) {
+	//sqlclosecheck:ignore closed by the caller through the connection
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func suppressedClose(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+	rows.Close() //sqlclosecheck:ignore
+}
+
+func otherLinterNotSuppressed(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") //nolint:errcheck // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func trailingDoesNotSuppressNextLine(ctx context.Context, db *sql.DB) {
+	_ = ctx                                                         //nolint:sqlclosecheck
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,36 @@
+package onlycategory
+
+import (
+	"context"
+	"database/sql"
+	"log"
+)
+
+func notClosed(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
+
+func notDeferred(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+
+	rows.Close() // want "Close should use defer"
+}
+
+func neverIterated(ctx context.Context, db *sql.DB) {
+	_, err := db.QueryContext(ctx, "DELETE FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+}
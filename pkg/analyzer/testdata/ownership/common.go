@@ -0,0 +1,29 @@
+package ownership
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+)
+
+var (
+	ctx context.Context
+	db  *sql.DB
+)
+
+type multiCloser []io.Closer
+
+func (m multiCloser) Close() error {
+	var errs []error
+	for _, c := range m {
+		errs = append(errs, c.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// MultiCloser takes ownership of the closers, closing the result closes them all
+func MultiCloser(closers ...io.Closer) io.Closer {
+	return multiCloser(closers)
+}
@@ -0,0 +1,38 @@
+package ownership
+
+import (
+	"log"
+)
+
+func closedByMultiCloser() {
+	stmt, err := db.PrepareContext(ctx, "SELECT name FROM users WHERE id = ?")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, 1)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	combined := MultiCloser(rows, stmt)
+	defer combined.Close()
+
+	for rows.Next() {
+	}
+}
+
+func multiCloserNotClosed() {
+	stmt, err := db.PrepareContext(ctx, "SELECT name FROM users WHERE id = ?") // want "database/sql.Stmt was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, 1) // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	combined := MultiCloser(rows, stmt)
+	_ = combined
+}
@@ -0,0 +1,57 @@
+package packages
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/packages/driver"
+)
+
+func cursorDeferred() {
+	cursor, err := driver.Open()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+	}
+}
+
+func cursorMissingClose() {
+	cursor, err := driver.Open() // want "driver.Cursor was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for cursor.Next() {
+	}
+}
+
+func sessionDeferred() {
+	session := driver.NewSession()
+	defer session.Close()
+
+	_ = session.ID()
+}
+
+func sessionMissingClose() {
+	session := driver.NewSession() // want "driver.Session was not closed"
+	_ = session.ID()
+}
+
+func handleNotChecked() {
+	handle := driver.Acquire()
+	_ = handle
+}
+
+func rowsStillChecked(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
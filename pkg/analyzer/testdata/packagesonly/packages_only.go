@@ -0,0 +1,29 @@
+package packagesonly
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/packages/driver"
+)
+
+func cursorMissingClose() {
+	cursor, err := driver.Open() // want "driver.Cursor was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for cursor.Next() {
+	}
+}
+
+func rowsNotChecked(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
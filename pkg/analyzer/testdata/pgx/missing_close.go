@@ -5,7 +5,7 @@ import (
 )
 
 func missingCloseTx() {
-	rows, err := pgxTx.Query(ctx, "SELECT username FROM users") // want "Rows/Stmt/NamedStmt was not closed"
+	rows, err := pgxTx.Query(ctx, "SELECT username FROM users") // want "github.com/jackc/pgx/v5.Rows was not closed"
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -14,7 +14,7 @@ func missingCloseTx() {
 }
 
 func missingCloseConn() {
-	rows, err := pgxConn.Query(ctx, "SELECT username FROM users") // want "Rows/Stmt/NamedStmt was not closed"
+	rows, err := pgxConn.Query(ctx, "SELECT username FROM users") // want "github.com/jackc/pgx/v5.Rows was not closed"
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -23,10 +23,30 @@ func missingCloseConn() {
 }
 
 func missingClosePgxPool() {
-	rows, err := pgxPool.Query(ctx, "SELECT username FROM users") // want "Rows/Stmt/NamedStmt was not closed"
+	rows, err := pgxPool.Query(ctx, "SELECT username FROM users") // want "github.com/jackc/pgx/v5.Rows was not closed"
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	_ = rows
 }
+
+func missingCloseConnIterated() {
+	rows, err := pgxConn.Query(ctx, "SELECT username FROM users") // want "github.com/jackc/pgx/v5.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
+
+func missingClosePgxPoolIterated() {
+	rows, err := pgxPool.Query(ctx, "SELECT username FROM users") // want "github.com/jackc/pgx/v5.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
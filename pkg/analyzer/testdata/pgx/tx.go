@@ -0,0 +1,28 @@
+package pgx
+
+import (
+	"log"
+)
+
+func txCommitted() error {
+	tx, err := pgxConn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "UPDATE users SET active = true"); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func txNotFinished() {
+	tx, err := pgxPool.Begin(ctx) // want "Tx was neither committed nor rolled back"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = tx.Exec(ctx, "UPDATE users SET active = true")
+}
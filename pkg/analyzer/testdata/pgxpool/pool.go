@@ -0,0 +1,64 @@
+package pgxpool
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Only pgxpool is imported, pgx.Rows comes from an indirect import
+
+func correctDeferPool(ctx context.Context, pool *pgxpool.Pool) {
+	rows, err := pool.Query(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func correctDeferPoolConn(ctx context.Context, pool *pgxpool.Pool) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func missingClosePool(ctx context.Context, pool *pgxpool.Pool) {
+	rows, err := pool.Query(ctx, "SELECT username FROM users") // want "github.com/jackc/pgx/v5.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
+
+func missingClosePoolConn(ctx context.Context, pool *pgxpool.Pool) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, "SELECT username FROM users") // want "github.com/jackc/pgx/v5.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
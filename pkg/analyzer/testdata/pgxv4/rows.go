@@ -0,0 +1,83 @@
+package pgxv4
+
+import (
+	"context"
+	"log"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	pgxv5 "github.com/jackc/pgx/v5"
+)
+
+func correctDefer(ctx context.Context, conn *pgx.Conn) {
+	rows, err := conn.Query(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func missingClose(ctx context.Context, conn *pgx.Conn) {
+	rows, err := conn.Query(ctx, "SELECT username FROM users") // want "github.com/jackc/pgx/v4.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
+
+func poolMissingClose(ctx context.Context, pool *pgxpool.Pool) {
+	rows, err := pool.Query(ctx, "SELECT username FROM users") // want "github.com/jackc/pgx/v4.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
+
+func txNotFinished(ctx context.Context, conn *pgx.Conn) {
+	tx, err := conn.Begin(ctx) // want "Tx was neither committed nor rolled back"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = tx.Exec(ctx, "UPDATE users SET active = true")
+}
+
+func txRolledBack(ctx context.Context, conn *pgx.Conn) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "UPDATE users SET active = true"); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Each version's Rows is closed by its own Close only
+func bothVersions(ctx context.Context, v4 *pgx.Conn, v5 *pgxv5.Conn) {
+	rows4, err := v4.Query(ctx, "SELECT username FROM users") // want "github.com/jackc/pgx/v4.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rows5, err := v5.Query(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows5.Close()
+
+	for rows4.Next() {
+	}
+	for rows5.Next() {
+	}
+}
@@ -0,0 +1,45 @@
+package verify
+
+import (
+	"context"
+	"database/sql"
+)
+
+func openRows(ctx context.Context, db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func closesOpened(ctx context.Context, db *sql.DB) {
+	rows, err := openRows(ctx, db)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func openLeakedRows(ctx context.Context, db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil // want "database/sql.Rows is returned but no caller closes it"
+}
+
+func leaksOpened(ctx context.Context, db *sql.DB) {
+	rows, err := openLeakedRows(ctx, db) // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
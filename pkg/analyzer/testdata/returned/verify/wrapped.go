@@ -0,0 +1,56 @@
+package verify
+
+import (
+	"context"
+	"database/sql"
+)
+
+type userIter struct {
+	rows *sql.Rows
+}
+
+func (it *userIter) Close() error {
+	return it.rows.Close()
+}
+
+func openUserRows(ctx context.Context, db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func closesWrapped(ctx context.Context, db *sql.DB) {
+	rows, err := openUserRows(ctx, db)
+	if err != nil {
+		return
+	}
+
+	it := &userIter{rows: rows}
+	defer it.Close()
+
+	for it.rows.Next() {
+	}
+}
+
+func openUnclosedUserRows(ctx context.Context, db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil // want "database/sql.Rows is returned but no caller closes it"
+}
+
+func leaksWrapped(ctx context.Context, db *sql.DB) {
+	rows, err := openUnclosedUserRows(ctx, db)
+	if err != nil {
+		return
+	}
+
+	it := userIter{rows: rows}
+	for it.rows.Next() {
+	}
+}
@@ -0,0 +1,86 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func arrayOfRowsClosed(ctx context.Context, db *sql.DB) {
+	var rs [2]*sql.Rows
+
+	a, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	rs[0] = a
+
+	b, err := db.QueryContext(ctx, "SELECT email FROM users")
+	if err != nil {
+		return
+	}
+	rs[1] = b
+
+	defer func() {
+		for i := range rs {
+			rs[i].Close()
+		}
+	}()
+
+	for a.Next() {
+	}
+	for b.Next() {
+	}
+}
+
+func arrayOfRowsRangeClosed(ctx context.Context, db *sql.DB) {
+	var rs [2]*sql.Rows
+
+	a, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	rs[0] = a
+
+	b, err := db.QueryContext(ctx, "SELECT email FROM users")
+	if err != nil {
+		return
+	}
+	rs[1] = b
+
+	for i := range rs {
+		defer rs[i].Close()
+	}
+
+	for a.Next() {
+	}
+	for b.Next() {
+	}
+}
+
+func arrayOfRowsNotClosed(ctx context.Context, db *sql.DB) {
+	var rs [1]*sql.Rows
+
+	a, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+	rs[0] = a
+
+	for rs[0].Next() {
+	}
+}
+
+func sliceOfRowsClosed(ctx context.Context, db *sql.DB) {
+	a, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	rs := []*sql.Rows{a}
+	for i := range rs {
+		defer rs[i].Close()
+	}
+
+	for a.Next() {
+	}
+}
@@ -0,0 +1,32 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"io"
+)
+
+func closeQuietly(c io.Closer) error {
+	return c.Close()
+}
+
+func closeQuietlyDeferred(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer func() { _ = closeQuietly(rows) }()
+
+	for rows.Next() {
+	}
+}
+
+func closeQuietlyMissing(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
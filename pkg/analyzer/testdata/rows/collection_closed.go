@@ -0,0 +1,97 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func appendedRowsClosedInLoop(ctx context.Context, db *sql.DB, queries []string) {
+	var rowsList []*sql.Rows
+	defer func() {
+		for _, rows := range rowsList {
+			rows.Close()
+		}
+	}()
+
+	for _, query := range queries {
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			return
+		}
+		rowsList = append(rowsList, rows)
+
+		for rows.Next() {
+		}
+	}
+}
+
+func appendedRowsClosedAfterLoop(ctx context.Context, db *sql.DB, queries []string) error {
+	rowsList := []*sql.Rows{}
+	for _, query := range queries {
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		rowsList = append(rowsList, rows)
+	}
+
+	for _, rows := range rowsList {
+		rows.Close()
+	}
+
+	return nil
+}
+
+func appendedRowsNotClosed(ctx context.Context, db *sql.DB, queries []string) int {
+	var rowsList []*sql.Rows
+	for _, query := range queries {
+		rows, err := db.QueryContext(ctx, query) // want "database/sql.Rows was not closed"
+		if err != nil {
+			return 0
+		}
+		rowsList = append(rowsList, rows)
+	}
+
+	return len(rowsList)
+}
+
+func mapOfRowsClosedInLoop(ctx context.Context, db *sql.DB, queries map[string]string) {
+	byName := map[string]*sql.Rows{}
+	defer func() {
+		for _, rows := range byName {
+			rows.Close()
+		}
+	}()
+
+	for name, query := range queries {
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			return
+		}
+		byName[name] = rows
+	}
+}
+
+func mapOfRowsClosedByKey(ctx context.Context, db *sql.DB) {
+	byName := map[string]*sql.Rows{}
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	byName["users"] = rows
+	defer byName["users"].Close()
+}
+
+func mapOfRowsNotClosed(ctx context.Context, db *sql.DB, queries map[string]string) int {
+	byName := map[string]*sql.Rows{}
+	for name, query := range queries {
+		rows, err := db.QueryContext(ctx, query) // want "database/sql.Rows was not closed"
+		if err != nil {
+			return 0
+		}
+		byName[name] = rows
+	}
+
+	return len(byName)
+}
@@ -0,0 +1,94 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func deferredConnClose(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "SET search_path TO tenant")
+	return err
+}
+
+func connNotClosed(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx) // want "database/sql.Conn was not closed"
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx, "SET search_path TO tenant")
+	return err
+}
+
+func connNotDeferred(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx, "SET search_path TO tenant")
+	conn.Close() // want "Close should use defer"
+
+	return err
+}
+
+func connRowsClosed(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+
+	return rows.Err()
+}
+
+func connRowsNotClosed(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+	}
+
+	return rows.Err()
+}
+
+func returnedConn(ctx context.Context, db *sql.DB) (*sql.Conn, error) {
+	return db.Conn(ctx)
+}
+
+func closeConn(conn *sql.Conn) { // want closeConn:"closesParams\\(\\[0\\]\\)"
+	_ = conn.Close()
+}
+
+func connPassedToClose(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeConn(conn)
+
+	return conn.PingContext(ctx)
+}
@@ -0,0 +1,50 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+type dbContextKey struct{}
+
+func dbFromContext(ctx context.Context) *sql.DB {
+	return ctx.Value(dbContextKey{}).(*sql.DB)
+}
+
+func contextDBRowsClosed(ctx context.Context) {
+	db := ctx.Value(dbContextKey{}).(*sql.DB)
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func contextDBRowsNotClosed(ctx context.Context) {
+	db, ok := ctx.Value(dbContextKey{}).(*sql.DB)
+	if !ok {
+		return
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func contextDBHelperRowsNotClosed(ctx context.Context) {
+	rows, err := dbFromContext(ctx).QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,76 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"log"
+)
+
+func cleanup(rows *sql.Rows, stmt *sql.Stmt) { // want cleanup:"closesParams\\(\\[0 1\\]\\)"
+	if err := rows.Close(); err != nil {
+		log.Println(err)
+	}
+	if err := stmt.Close(); err != nil {
+		log.Println(err)
+	}
+}
+
+func cleanupRows(rows *sql.Rows) {
+	closeRows(rows)
+}
+
+func closeRows(rows *sql.Rows) { // want closeRows:"closesParams\\(\\[0\\]\\)"
+	if err := rows.Close(); err != nil {
+		log.Println(err)
+	}
+}
+
+// logErr closes rows of its own, not the ones passed to it
+func logErr(ctx context.Context, rows *sql.Rows) {
+	audit, err := db.QueryContext(ctx, "SELECT 1")
+	if err != nil {
+		return
+	}
+	defer audit.Close()
+
+	log.Println(rows.Err())
+}
+
+func deferredCleanup(ctx context.Context, db *sql.DB) {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE age = ?")
+	if err != nil {
+		return
+	}
+
+	rows, err := stmt.QueryContext(ctx, 27)
+	if err != nil {
+		stmt.Close() // want "Close should use defer"
+		return
+	}
+	defer cleanup(rows, stmt)
+
+	for rows.Next() {
+	}
+}
+
+func deferredNestedCleanup(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer cleanupRows(rows)
+
+	for rows.Next() {
+	}
+}
+
+func deferredCleanupOfOtherRows(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+	defer logErr(ctx, rows)
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,75 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"io"
+)
+
+func closeAll(cs ...io.Closer) {
+	for _, c := range cs {
+		c.Close()
+	}
+}
+
+func deferredCloseAllOfRowsAndStmt(ctx context.Context, db *sql.DB) {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE id = ?")
+	if err != nil {
+		return
+	}
+
+	rows, err := stmt.QueryContext(ctx, 1)
+	if err != nil {
+		closeAll(stmt)
+		return
+	}
+	defer closeAll(rows, stmt)
+
+	for rows.Next() {
+	}
+}
+
+func closeAllRows(rs ...*sql.Rows) {
+	for _, r := range rs {
+		r.Close()
+	}
+}
+
+func deferredCloseAllOfTypedRows(ctx context.Context, db *sql.DB) {
+	users, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	orders, err := db.QueryContext(ctx, "SELECT id FROM orders")
+	if err != nil {
+		closeAllRows(users)
+		return
+	}
+	defer closeAllRows(users, orders)
+
+	for users.Next() {
+	}
+	for orders.Next() {
+	}
+}
+
+func countAll(cs ...io.Closer) int {
+	n := 0
+	for range cs {
+		n++
+	}
+
+	return n
+}
+
+func deferredHelperNotClosingVariadic(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+	defer countAll(rows)
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,48 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func deferredClosureClosingRowsAndStmt(ctx context.Context, db *sql.DB) {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE id = ?")
+	if err != nil {
+		return
+	}
+
+	rows, err := stmt.QueryContext(ctx, 1)
+	if err != nil {
+		stmt.Close()
+		return
+	}
+	defer func() {
+		rows.Close()
+		stmt.Close()
+	}()
+
+	for rows.Next() {
+	}
+}
+
+func deferredClosureClosingOnlyStmt(ctx context.Context, db *sql.DB) {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE id = ?")
+	if err != nil {
+		return
+	}
+
+	rows, err := stmt.QueryContext(ctx, 1) // want "database/sql.Rows was not closed"
+	if err != nil {
+		stmt.Close()
+		return
+	}
+	defer func() {
+		if rows.Err() != nil {
+			return
+		}
+		stmt.Close()
+	}()
+
+	for rows.Next() {
+	}
+}
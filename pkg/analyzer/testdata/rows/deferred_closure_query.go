@@ -0,0 +1,37 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func processStats(rows *sql.Rows) {
+	for rows.Next() {
+	}
+}
+
+// The deferred closure is analyzed as a function of its own, the condition
+// doesn't hide the rows it leaks
+func conditionalQueryInDeferredClosure(ctx context.Context, db *sql.DB, needStats bool) {
+	defer func() {
+		if needStats {
+			rows, _ := db.QueryContext(ctx, "SELECT count(*) FROM users") // want "database/sql.Rows was not closed"
+			processStats(rows)
+			_ = rows.Err()
+		}
+	}()
+}
+
+func conditionalQueryInDeferredClosureClosed(ctx context.Context, db *sql.DB, needStats bool) {
+	defer func() {
+		if needStats {
+			rows, err := db.QueryContext(ctx, "SELECT count(*) FROM users")
+			if err != nil {
+				return
+			}
+			defer rows.Close()
+
+			processStats(rows)
+		}
+	}()
+}
@@ -0,0 +1,34 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func openAudit(ctx context.Context, db *sql.DB) *sql.Rows {
+	rows, _ := db.QueryContext(ctx, "SELECT event FROM audit")
+	return rows
+}
+
+func openLeakyAudit(ctx context.Context, db *sql.DB) *sql.Rows {
+	count, _ := db.QueryContext(ctx, "SELECT count(*) FROM audit") // want "database/sql.Rows was not closed"
+	for count.Next() {
+	}
+
+	rows, _ := db.QueryContext(ctx, "SELECT event FROM audit")
+	return rows
+}
+
+// The rows opened by the deferred expression are closed when the function returns
+func deferredCloseOnFreshTarget(ctx context.Context, db *sql.DB) {
+	defer openAudit(ctx, db).Close()
+}
+
+// The leak inside openLeakyAudit is reported there, not here
+func deferredCloseOnFreshTargetOfLeakyHelper(ctx context.Context, db *sql.DB) {
+	defer openLeakyAudit(ctx, db).Close()
+}
+
+func freshTargetNotClosed(ctx context.Context, db *sql.DB) {
+	openAudit(ctx, db).Next() // want "database/sql.Rows was not closed"
+}
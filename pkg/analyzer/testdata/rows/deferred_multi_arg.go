@@ -0,0 +1,54 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"log"
+)
+
+func closeWith(r *sql.Rows, tag string) { // want closeWith:"closesParams\\(\\[0\\]\\)"
+	log.Println(tag)
+	r.Close()
+}
+
+func logWith(tag string, r *sql.Rows) {
+	log.Println(tag, r.Err())
+}
+
+func closeTagged(tag string, r *sql.Rows) { // want closeTagged:"closesParams\\(\\[1\\]\\)"
+	log.Println(tag)
+	r.Close()
+}
+
+func deferredMultiArgClose(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer closeWith(rows, "context")
+
+	for rows.Next() {
+	}
+}
+
+func deferredMultiArgNoClose(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+	defer logWith("context", rows)
+
+	for rows.Next() {
+	}
+}
+
+func deferredMultiArgCloseSecond(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer closeTagged("context", rows)
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,69 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func queryBoth(ctx context.Context, db *sql.DB) (*sql.Rows, *sql.Rows, error) {
+	users, err := db.QueryContext(ctx, "SELECT id FROM users")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups, err := db.QueryContext(ctx, "SELECT id FROM groups")
+	if err != nil {
+		users.Close()
+		return nil, nil, err
+	}
+
+	return users, groups, nil
+}
+
+func bothNotClosed(ctx context.Context, db *sql.DB) {
+	users, groups, err := queryBoth(ctx, db) // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for users.Next() {
+	}
+	for groups.Next() {
+	}
+}
+
+func onlyOneClosed(ctx context.Context, db *sql.DB) {
+	users, groups, err := queryBoth(ctx, db) // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+	defer users.Close()
+
+	for users.Next() {
+	}
+	for groups.Next() {
+	}
+}
+
+func bothClosed(ctx context.Context, db *sql.DB) {
+	users, groups, err := queryBoth(ctx, db)
+	if err != nil {
+		return
+	}
+	defer users.Close()
+	defer groups.Close()
+
+	for users.Next() {
+	}
+	for groups.Next() {
+	}
+}
+
+func sameLineNotClosed(ctx context.Context, db *sql.DB) {
+	users, _ := db.QueryContext(ctx, "SELECT id FROM users"); groups, _ := db.QueryContext(ctx, "SELECT id FROM groups") // want "database/sql.Rows was not closed" "database/sql.Rows was not closed"
+
+	for users.Next() {
+	}
+	for groups.Next() {
+	}
+}
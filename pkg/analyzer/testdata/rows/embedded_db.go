@@ -0,0 +1,60 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func embeddedDBLeak(ctx context.Context, db *sql.DB) {
+	x := struct{ *sql.DB }{db}
+
+	rows, err := x.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func embeddedDBClosed(ctx context.Context, db *sql.DB) {
+	x := struct{ *sql.DB }{db}
+
+	rows, err := x.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func embeddedDBMethodValue(ctx context.Context, db *sql.DB) {
+	x := struct{ *sql.DB }{db}
+	query := x.QueryContext
+
+	rows, err := query(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+type embeddedQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func embeddedDBInterface(ctx context.Context, db *sql.DB) {
+	var q embeddedQuerier = struct{ *sql.DB }{db}
+
+	rows, err := q.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
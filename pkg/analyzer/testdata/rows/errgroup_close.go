@@ -0,0 +1,63 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+func closedInGroup(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+
+	var g errgroup.Group
+	g.Go(func() error {
+		defer rows.Close()
+
+		for rows.Next() {
+		}
+
+		return rows.Err()
+	})
+
+	return g.Wait()
+}
+
+func closedOnceInGroup(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+
+	var once sync.Once
+	closeRows := func() error {
+		once.Do(func() { rows.Close() })
+		return nil
+	}
+
+	var g errgroup.Group
+	g.Go(closeRows)
+
+	return g.Wait()
+}
+
+func notClosedInGroup(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return err
+	}
+
+	var g errgroup.Group
+	g.Go(func() error {
+		for rows.Next() {
+		}
+
+		return rows.Err()
+	})
+
+	return g.Wait()
+}
@@ -0,0 +1,42 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"io"
+)
+
+func closeAndReturn[T io.Closer](c T) T {
+	c.Close()
+	return c
+}
+
+func genericCloseAndReturn(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	rows = closeAndReturn(rows)
+	_ = rows
+}
+
+func identityAndReturn[T io.Closer](c T) T {
+	return c
+}
+
+func genericReturnNotClosed(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	rows = identityAndReturn(rows)
+	_ = rows
+}
@@ -0,0 +1,80 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+var sharedRows *sql.Rows
+
+func deferredCloseOfGlobalRows(ctx context.Context, db *sql.DB) {
+	var err error
+	sharedRows, err = db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer sharedRows.Close()
+
+	for sharedRows.Next() {
+	}
+}
+
+var cachedRows *sql.Rows
+
+func openCachedRows(ctx context.Context, db *sql.DB) error {
+	var err error
+	cachedRows, err = db.QueryContext(ctx, "SELECT username FROM users")
+	return err
+}
+
+func closeCachedRows() {
+	if cachedRows != nil {
+		cachedRows.Close()
+	}
+}
+
+var leakedRows *sql.Rows
+
+func globalRowsNeverClosed(ctx context.Context, db *sql.DB) {
+	var err error
+	leakedRows, err = db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for leakedRows.Next() {
+	}
+}
+
+var nonDeferredRows *sql.Rows
+
+func nonDeferredCloseOfGlobalRows(ctx context.Context, db *sql.DB) {
+	var err error
+	nonDeferredRows, err = db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for nonDeferredRows.Next() {
+	}
+	nonDeferredRows.Close() // want "Close should use defer"
+}
+
+// CurrentRows is closed by the importers of the package
+var CurrentRows *sql.Rows
+
+func openCurrentRows(ctx context.Context, db *sql.DB) error {
+	var err error
+	CurrentRows, err = db.QueryContext(ctx, "SELECT username FROM users")
+	return err
+}
+
+func assignExportedGlobalRows(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+
+	CurrentRows = rows
+	return nil
+}
@@ -0,0 +1,40 @@
+package rows
+
+import (
+	"log"
+)
+
+func gotoCleanup() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			goto cleanup
+		}
+	}
+
+cleanup:
+	rows.Close() // want "Close should use defer"
+}
+
+func gotoSkipsCleanup(skip bool) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if skip {
+		goto done
+	}
+
+	for rows.Next() {
+	}
+	rows.Close() // want "Close should use defer"
+
+done:
+	log.Print("done")
+}
@@ -0,0 +1,65 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+type User struct {
+	Name string
+}
+
+type ListUsersRequest struct{}
+
+// UserService_ListUsersServer models the server stream generated by protoc-gen-go-grpc
+type UserService_ListUsersServer interface {
+	Send(*User) error
+	Context() context.Context
+}
+
+type userServer struct {
+	db *sql.DB
+}
+
+func (s *userServer) ListUsers(_ *ListUsersRequest, stream UserService_ListUsersServer) error {
+	rows, err := s.db.QueryContext(stream.Context(), "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		user := &User{}
+		if err := rows.Scan(&user.Name); err != nil {
+			return err
+		}
+
+		if err := stream.Send(user); err != nil {
+			return err
+		}
+	}
+
+	rows.Close() // want "Close should use defer"
+
+	return rows.Err()
+}
+
+func (s *userServer) ListUsersDeferred(_ *ListUsersRequest, stream UserService_ListUsersServer) error {
+	rows, err := s.db.QueryContext(stream.Context(), "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user := &User{}
+		if err := rows.Scan(&user.Name); err != nil {
+			return err
+		}
+
+		if err := stream.Send(user); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
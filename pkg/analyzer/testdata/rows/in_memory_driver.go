@@ -0,0 +1,51 @@
+package rows
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+)
+
+type memDriver struct{}
+
+func (memDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("not implemented")
+}
+
+func init() {
+	sql.Register("memdb", memDriver{})
+}
+
+func inMemoryMissingClose() {
+	memDB, err := sql.Open("memdb", "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer memDB.Close()
+
+	rows, err := memDB.Query("SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
+
+func inMemoryClosed() {
+	memDB, err := sql.Open("memdb", "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer memDB.Close()
+
+	rows, err := memDB.Query("SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,108 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"io"
+)
+
+func deferredCloseOnConversion(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer io.Closer(rows).Close()
+
+	for rows.Next() {
+	}
+}
+
+func closeOnConversionNotDeferred(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	io.Closer(rows).Close() // want "Close should use defer"
+}
+
+func deferredCloseThroughCloser(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	var c io.Closer = rows
+	defer c.Close()
+
+	for rows.Next() {
+	}
+}
+
+func deferredCloseThroughAssertion(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	var v any = rows
+	defer v.(io.Closer).Close()
+
+	for rows.Next() {
+	}
+}
+
+type errCloser interface {
+	Close() error
+}
+
+func deferredCloseThroughChangedInterface(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	var c io.Closer = rows
+	var ec errCloser = c
+	defer ec.Close()
+
+	for rows.Next() {
+	}
+}
+
+func closerNeverClosed(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	var v any = rows
+	if _, ok := v.(io.Closer); !ok {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+type rowsCloser interface {
+	io.Closer
+	Next() bool
+}
+
+func deferredCloseThroughNarrowedInterface(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	var rc rowsCloser = rows
+	var c io.Closer = rc
+	defer c.Close()
+
+	for rc.Next() {
+	}
+}
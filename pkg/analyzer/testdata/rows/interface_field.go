@@ -0,0 +1,39 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"io"
+)
+
+type closerHolder struct {
+	c io.Closer
+}
+
+func deferredInterfaceFieldClose(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	var s closerHolder
+	s.c = rows
+	defer s.c.Close()
+
+	for rows.Next() {
+	}
+}
+
+func interfaceFieldNotClosed(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	var s closerHolder
+	s.c = rows
+	_ = s
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,30 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func deferredLocalCleanupClosure(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	cleanup := func() { rows.Close() }
+	defer cleanup()
+
+	for rows.Next() {
+	}
+}
+
+func localCleanupClosureNeverCalled(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+	cleanup := func() { rows.Close() }
+	_ = cleanup
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,45 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func processRows(rows *sql.Rows) {
+	for rows.Next() {
+	}
+}
+
+func perIterationCloseOfStmtRows(ctx context.Context, db *sql.DB, ids []int) {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE id = ?")
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	for _, id := range ids {
+		rows, err := stmt.QueryContext(ctx, id)
+		if err != nil {
+			return
+		}
+		processRows(rows)
+		rows.Close()
+	}
+}
+
+func missingPerIterationCloseOfStmtRows(ctx context.Context, db *sql.DB, ids []int) {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE id = ?")
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	for _, id := range ids {
+		rows, err := stmt.QueryContext(ctx, id) // want "database/sql.Rows was not closed"
+		if err != nil {
+			return
+		}
+		for rows.Next() {
+		}
+	}
+}
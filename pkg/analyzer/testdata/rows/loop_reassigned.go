@@ -0,0 +1,172 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func deferredCloseInLoop(db *sql.DB, queries []string) {
+	for _, q := range queries {
+		rows, err := db.Query(q)
+		if err != nil {
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+		}
+	}
+}
+
+func closedEachIteration(ctx context.Context, db *sql.DB, queries []string) {
+	for _, q := range queries {
+		rows, err := db.QueryContext(ctx, q)
+		if err != nil {
+			continue
+		}
+
+		for rows.Next() {
+		}
+		rows.Close()
+	}
+}
+
+func reassignedAndClosedEachIteration(ctx context.Context, db *sql.DB, queries []string) error {
+	var rows *sql.Rows
+	var err error
+	for _, q := range queries {
+		rows, err = db.QueryContext(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func closedEachIterationByClosure(ctx context.Context, db *sql.DB, queries []string) error {
+	for _, q := range queries {
+		err := func() error {
+			rows, err := db.QueryContext(ctx, q)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+			}
+			return rows.Err()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func notClosedInLoop(ctx context.Context, db *sql.DB, queries []string) {
+	for _, q := range queries {
+		rows, err := db.QueryContext(ctx, q) // want "database/sql.Rows was not closed"
+		if err != nil {
+			return
+		}
+
+		for rows.Next() {
+		}
+	}
+}
+
+func onlyLastIterationClosed(ctx context.Context, db *sql.DB, queries []string) {
+	var rows *sql.Rows
+	var err error
+	for _, q := range queries {
+		rows, err = db.QueryContext(ctx, q) // want "database/sql.Rows was not closed"
+		if err != nil {
+			return
+		}
+
+		for rows.Next() {
+		}
+	}
+
+	if rows != nil {
+		rows.Close()
+	}
+}
+
+func closedOnSomeIterations(ctx context.Context, db *sql.DB, queries []string) {
+	for i, q := range queries {
+		rows, err := db.QueryContext(ctx, q) // want "database/sql.Rows was not closed"
+		if err != nil {
+			return
+		}
+
+		for rows.Next() {
+		}
+		if i%2 == 0 {
+			rows.Close()
+		}
+	}
+}
+
+func overwrittenOnRetry(ctx context.Context, db *sql.DB, queries []string) {
+	var rows *sql.Rows
+	for _, q := range queries {
+		var err error
+		rows, err = db.QueryContext(ctx, q) // want "database/sql.Rows was not closed"
+		if err != nil {
+			continue
+		}
+	}
+
+	if rows != nil {
+		rows.Close()
+	}
+}
+
+func createdOnceInLoop(ctx context.Context, db *sql.DB, queries []string) error {
+	var rows *sql.Rows
+	for _, q := range queries {
+		if rows == nil {
+			var err error
+			rows, err = db.QueryContext(ctx, q)
+			if err != nil {
+				return err
+			}
+		}
+
+		rows.Next()
+	}
+
+	if rows != nil {
+		return rows.Close()
+	}
+
+	return nil
+}
+
+func previousClosedByNextIteration(ctx context.Context, db *sql.DB, queries []string) {
+	var prev *sql.Rows
+	for _, q := range queries {
+		rows, err := db.QueryContext(ctx, q)
+		if err != nil {
+			break
+		}
+
+		if prev != nil {
+			prev.Close()
+		}
+		prev = rows
+	}
+
+	if prev != nil {
+		prev.Close()
+	}
+}
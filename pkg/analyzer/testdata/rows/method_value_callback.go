@@ -0,0 +1,38 @@
+package rows
+
+import (
+	"log"
+)
+
+type router struct {
+	routes map[string]func()
+}
+
+func (r *router) GET(path string, handler func()) {
+	r.routes[path] = handler
+}
+
+type handlers struct{}
+
+func (h *handlers) handleLeak() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_ = rows
+}
+
+func (h *handlers) handleClosed() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+}
+
+func registerHandlers(r *router) {
+	h := &handlers{}
+	r.GET("/leak", h.handleLeak)
+	r.GET("/closed", h.handleClosed)
+}
@@ -0,0 +1,117 @@
+package rows
+
+import (
+	"database/sql"
+	"io"
+	"log"
+)
+
+func closedInDeferredFuncLiteral() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() { rows.Close() }()
+
+	for rows.Next() {
+	}
+}
+
+func closedByDeferredMethodValue() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	cleanup := rows.Close
+	defer cleanup()
+
+	for rows.Next() {
+	}
+}
+
+func closedByMethodValueInFuncLiteral() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	cleanup := rows.Close
+	defer func() { _ = cleanup() }()
+
+	for rows.Next() {
+	}
+}
+
+func closedByInterfaceMethodValue() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	var closer io.Closer = rows
+	cleanup := closer.Close
+	defer cleanup()
+
+	for rows.Next() {
+	}
+}
+
+func closedByDeferredMethodExpression() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer (*sql.Rows).Close(rows)
+
+	for rows.Next() {
+	}
+}
+
+func closedByMethodExpressionValue() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	closeRows := (*sql.Rows).Close
+	defer closeRows(rows)
+
+	for rows.Next() {
+	}
+}
+
+func runDeferred(fn func() error) {
+	defer fn()
+}
+
+func closedByMethodValuePassed() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+	runDeferred(rows.Close)
+}
+
+func methodValueNeverInvoked() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+	cleanup := rows.Close
+	_ = cleanup
+
+	for rows.Next() {
+	}
+}
+
+func methodValueCapturedNotCalled() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+	cleanup := rows.Close
+	defer func() { _ = cleanup }()
+
+	for rows.Next() {
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 func missingClose() {
 	age := 27
-	rows, err := db.QueryContext(ctx, "SELECT name FROM users WHERE age=?", age) // want "Rows/Stmt/NamedStmt was not closed"
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users WHERE age=?", age) // want "database/sql.Rows was not closed"
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,64 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+type nestedCursor struct {
+	rows *sql.Rows
+}
+
+type nestedPage struct {
+	cursor nestedCursor
+}
+
+func deferredCloseThroughNestedSelector(ctx context.Context, db *sql.DB) error {
+	var p struct {
+		page nestedPage
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+	p.page.cursor.rows = rows
+	defer p.page.cursor.rows.Close()
+
+	for p.page.cursor.rows.Next() {
+	}
+
+	return p.page.cursor.rows.Err()
+}
+
+type nestedCloserHolder struct {
+	inner closerHolder
+}
+
+func deferredInterfaceCloseThroughNestedSelector(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	var s nestedCloserHolder
+	s.inner.c = rows
+	defer s.inner.c.Close()
+
+	for rows.Next() {
+	}
+}
+
+func nestedInterfaceFieldNotClosed(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	var s nestedCloserHolder
+	s.inner.c = rows
+	_ = s.inner.c
+
+	for rows.Next() {
+	}
+}
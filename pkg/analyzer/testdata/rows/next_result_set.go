@@ -0,0 +1,38 @@
+package rows
+
+import (
+	"log"
+)
+
+func nextResultSetMissingClose() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users; SELECT name FROM admins") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for {
+		for rows.Next() {
+		}
+
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+}
+
+func nextResultSetClosed() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users; SELECT name FROM admins")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for {
+		for rows.Next() {
+		}
+
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+}
@@ -14,7 +14,7 @@ func passedAndClosed() {
 	closedPassed(rows)
 }
 
-func closedPassed(rows *sql.Rows) {
+func closedPassed(rows *sql.Rows) { // want closedPassed:"closesParams\\(\\[0\\]\\)"
 	rows.Close()
 }
 
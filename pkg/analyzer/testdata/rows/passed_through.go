@@ -0,0 +1,81 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"log"
+)
+
+func closeIt(r *sql.Rows) { // want closeIt:"closesParams\\(\\[0\\]\\)"
+	r.Close()
+}
+
+func finishRows(r *sql.Rows) {
+	if err := r.Err(); err != nil {
+		log.Println(err)
+	}
+
+	closeIt(r)
+}
+
+func inspectRows(r *sql.Rows) {
+	log.Println(r.Err())
+}
+
+// drainRows recurses into itself, which isn't followed again
+func drainRows(r *sql.Rows, n int) {
+	if n > 0 {
+		drainRows(r, n-1)
+	}
+}
+
+func closedByHelperThenUsed(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	closeIt(rows)
+	log.Println(rows.Err())
+}
+
+func closedThroughHelperChain(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	finishRows(rows)
+	log.Println(rows.Err())
+}
+
+func inspectedByHelper(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+
+	inspectRows(rows)
+	log.Println(rows.Err())
+}
+
+func drainedRecursively(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	drainRows(rows, 3)
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,58 @@
+package rows
+
+import (
+	"database/sql"
+)
+
+func mergedClosedAfter(db *sql.DB, byName bool) {
+	var rows *sql.Rows
+	var err error
+	if byName {
+		rows, err = db.Query("SELECT id FROM users ORDER BY name")
+	} else {
+		rows, err = db.Query("SELECT id FROM users ORDER BY id")
+	}
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func mergedClosedOnOneBranch(db *sql.DB, byName bool) {
+	var rows *sql.Rows
+	var err error
+	if byName {
+		rows, err = db.Query("SELECT id FROM users ORDER BY name") // want "database/sql.Rows was not closed"
+	} else {
+		rows, err = db.Query("SELECT id FROM users ORDER BY id") // want "database/sql.Rows was not closed"
+	}
+	if err != nil {
+		return
+	}
+
+	if byName {
+		defer rows.Close()
+	}
+
+	for rows.Next() {
+	}
+}
+
+func mergedNeverClosed(db *sql.DB, byName bool) {
+	var rows *sql.Rows
+	var err error
+	if byName {
+		rows, err = db.Query("SELECT id FROM users ORDER BY name") // want "database/sql.Rows was not closed"
+	} else {
+		rows, err = db.Query("SELECT id FROM users ORDER BY id") // want "database/sql.Rows was not closed"
+	}
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
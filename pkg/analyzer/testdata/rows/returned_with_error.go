@@ -0,0 +1,80 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type noColumnsError struct{}
+
+func (noColumnsError) Error() string { return "no columns" }
+
+func wrapQueryErr(op string, err error) error {
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+func returnedWithWrappedError(ctx context.Context, db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := rows.Columns(); err != nil {
+		return rows, fmt.Errorf("columns: %w", err)
+	}
+
+	return rows, nil
+}
+
+func returnedWithHelperError(ctx context.Context, db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := rows.Columns(); err != nil {
+		return rows, wrapQueryErr("columns", err)
+	}
+
+	return rows, nil
+}
+
+func closedOnOneBranchReturnedWithErrorOnAnother(ctx context.Context, db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return nil, err
+	}
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return rows, errors.New("no columns")
+	}
+
+	if len(cols) == 0 {
+		_ = rows.Close()
+		return nil, noColumnsError{}
+	}
+
+	return rows, nil
+}
+
+func returnedWithQueryError(ctx context.Context, db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	return rows, err
+}
+
+func closedBeforeErrorReturn(ctx context.Context, db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := rows.Columns(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("columns: %w", err)
+	}
+
+	return rows, nil
+}
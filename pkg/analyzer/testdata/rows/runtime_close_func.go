@@ -0,0 +1,42 @@
+package rows
+
+import (
+	"database/sql"
+	"log"
+)
+
+func tracedClose(rows *sql.Rows) func() error {
+	return func() error {
+		log.Print("closing rows")
+		return rows.Close()
+	}
+}
+
+func closeFuncChosenAtRuntime(traced bool) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	closeFn := rows.Close
+	if traced {
+		closeFn = tracedClose(rows)
+	}
+	defer closeFn()
+
+	for rows.Next() {
+	}
+}
+
+func closeFuncChosenAtRuntimeNoop(skip bool) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	closeFn := rows.Close
+	if skip {
+		closeFn = func() error { return nil }
+	}
+	defer closeFn()
+}
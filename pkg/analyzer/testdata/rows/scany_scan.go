@@ -0,0 +1,74 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/georgysavva/scany/v2/sqlscan"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type scannedUser struct {
+	Username string
+}
+
+func scanAllClosesRows(ctx context.Context, db *sql.DB) ([]scannedUser, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	var users []scannedUser
+	if err := sqlscan.ScanAll(&users, rows); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func scanOneClosesRows(ctx context.Context, db *sql.DB) (*scannedUser, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users LIMIT 1")
+	if err != nil {
+		return nil, err
+	}
+
+	var user scannedUser
+	if err := sqlscan.ScanOne(&user, rows); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func pgxScanAllClosesRows(ctx context.Context, pool *pgxpool.Pool) ([]scannedUser, error) {
+	rows, err := pool.Query(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	var users []scannedUser
+	if err := pgxscan.ScanAll(&users, rows); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func scanRowKeepsRowsOpen(ctx context.Context, db *sql.DB) ([]scannedUser, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return nil, err
+	}
+
+	var users []scannedUser
+	for rows.Next() {
+		var user scannedUser
+		if err := sqlscan.ScanRow(&user, rows); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
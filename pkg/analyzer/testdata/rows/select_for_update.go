@@ -0,0 +1,47 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func selectForUpdateClosed(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM users WHERE active FOR UPDATE")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET active = false WHERE active"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func selectForUpdateCommitted(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM users WHERE active FOR UPDATE") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+	}
+
+	return tx.Commit()
+}
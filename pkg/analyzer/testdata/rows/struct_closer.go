@@ -0,0 +1,92 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+type resultSet struct {
+	stmt *sql.Stmt
+	rows *sql.Rows
+}
+
+func (r *resultSet) Close() error {
+	if err := r.rows.Close(); err != nil {
+		return err
+	}
+
+	return r.stmt.Close()
+}
+
+type forgetfulResultSet struct {
+	stmt *sql.Stmt
+	rows *sql.Rows
+}
+
+func (r *forgetfulResultSet) Close() error {
+	return r.stmt.Close()
+}
+
+type valueResultSet struct {
+	rows *sql.Rows
+}
+
+func (r valueResultSet) Close() error {
+	return r.rows.Close()
+}
+
+type delegatingResultSet struct {
+	rows *sql.Rows
+}
+
+func (r *delegatingResultSet) Close() {
+	_ = closeQuietly(r.rows)
+}
+
+func openResultSet(ctx context.Context, db *sql.DB) (*resultSet, error) {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		stmt.Close() // want "Close should use defer"
+		return nil, err
+	}
+
+	return &resultSet{stmt: stmt, rows: rows}, nil
+}
+
+func openForgetfulResultSet(ctx context.Context, db *sql.DB) (*forgetfulResultSet, error) {
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx) // want "database/sql.Rows was not closed"
+	if err != nil {
+		stmt.Close() // want "Close should use defer"
+		return nil, err
+	}
+
+	return &forgetfulResultSet{stmt: stmt, rows: rows}, nil
+}
+
+func openValueResultSet(ctx context.Context, db *sql.DB) (*valueResultSet, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	return &valueResultSet{rows: rows}, nil
+}
+
+func openDelegatingResultSet(ctx context.Context, db *sql.DB) (*delegatingResultSet, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+
+	return &delegatingResultSet{rows: rows}, nil
+}
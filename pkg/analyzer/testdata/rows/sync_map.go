@@ -0,0 +1,41 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// Rows stored in a sync.Map are handed over to the map, like rows stored in a
+// struct field. They're trusted to be closed by whoever loads them, even when
+// used after the store.
+func syncMapClosedAfterLoad(ctx context.Context, db *sql.DB) {
+	var m sync.Map
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	m.Store("users", rows)
+
+	if v, ok := m.Load("users"); ok {
+		defer v.(*sql.Rows).Close()
+	}
+}
+
+func syncMapUsedAfterStore(ctx context.Context, db *sql.DB) {
+	var m sync.Map
+
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	m.Store("users", rows)
+
+	for rows.Next() {
+	}
+
+	if v, ok := m.Load("users"); ok {
+		defer v.(*sql.Rows).Close()
+	}
+}
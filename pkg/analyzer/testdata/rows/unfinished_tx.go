@@ -0,0 +1,79 @@
+package rows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func txRolledBackByDefer(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM users"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func txEndedOnEveryPath(ctx context.Context, db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM users"); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func beginUsersTx(ctx context.Context, db *sql.DB) (*sql.Tx, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func commitTx(tx *sql.Tx) error { // want commitTx:"closesParams\\(\\[0\\]\\)"
+	return tx.Commit()
+}
+
+func txCommittedByHelper(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	return commitTx(tx)
+}
+
+func txNeverEnded(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil) // want "Tx was neither committed nor rolled back"
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM users")
+	return err
+}
+
+func txLeftOpenOnError(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil) // want "Tx was neither committed nor rolled back"
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM users"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,39 @@
+package rows
+
+import "database/sql"
+
+func closedAfterReturn(db *sql.DB) {
+	rows, err := db.Query("SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+	return
+
+	rows.Close()
+}
+
+func deferredAfterReturn(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return err
+	}
+	return rows.Err()
+
+	defer rows.Close()
+	return nil
+}
+
+func closedBeforeReturn(db *sql.DB) {
+	rows, err := db.Query("SELECT name FROM users")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+	return
+}
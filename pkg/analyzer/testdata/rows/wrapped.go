@@ -0,0 +1,84 @@
+package rows
+
+import (
+	"database/sql"
+	"log"
+)
+
+type rowsWrapper struct {
+	rows *sql.Rows
+}
+
+func (w *rowsWrapper) Close() error {
+	return w.rows.Close()
+}
+
+func wrapRows(rows *sql.Rows) *rowsWrapper {
+	return &rowsWrapper{rows: rows}
+}
+
+func identityRows(rows *sql.Rows) *sql.Rows {
+	return rows
+}
+
+func closedThroughWrapper() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := wrapRows(rows)
+	defer w.Close()
+
+	for rows.Next() {
+	}
+}
+
+func closedThroughIdentity() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	wrapped := identityRows(rows)
+	defer wrapped.Close()
+
+	for rows.Next() {
+	}
+}
+
+func leakedThroughWrapper() {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := wrapRows(rows)
+	_ = w
+
+	for rows.Next() {
+	}
+}
+
+func secondRows(first, second *sql.Rows) *sql.Rows {
+	_ = first.Next()
+	return second
+}
+
+func otherArgumentReturned() {
+	users, err := db.QueryContext(ctx, "SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	groups, err := db.QueryContext(ctx, "SELECT name FROM groups")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	r := secondRows(users, groups)
+	defer r.Close()
+
+	for users.Next() {
+	}
+}
@@ -0,0 +1,120 @@
+package rowserr
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func errChecked(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+func errCheckedInIf(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func errNotChecked(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() { // want "rows.Err\\(\\) not checked after iteration"
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func errCheckedBeforeIteration(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	if err := rows.Err(); err != nil {
+		log.Fatal(err)
+	}
+	for rows.Next() { // want "rows.Err\\(\\) not checked after iteration"
+	}
+}
+
+func checkRows(rows *sql.Rows) error {
+	return rows.Err()
+}
+
+func errCheckedByHelper(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+
+	return checkRows(rows)
+}
+
+func singleNext(ctx context.Context, db *sql.DB) (string, error) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users LIMIT 1")
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var name string
+	if rows.Next() { // want "rows.Err\\(\\) not checked after iteration"
+		err = rows.Scan(&name)
+	}
+
+	return name, err
+}
+
+func pgxNotChecked(ctx context.Context, conn *pgx.Conn) {
+	rows, err := conn.Query(ctx, "SELECT username FROM users")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,16 @@
+package severity
+
+import (
+	"context"
+	"database/sql"
+)
+
+func leakRows(ctx context.Context, db *sql.DB) {
+	rows, _ := db.QueryContext(ctx, "SELECT 1") // want "database/sql.Rows was not closed"
+	_ = rows
+}
+
+func leakStmt(ctx context.Context, db *sql.DB) {
+	stmt, _ := db.PrepareContext(ctx, "SELECT 1") // want "database/sql.Stmt was not closed"
+	_ = stmt
+}
@@ -0,0 +1,61 @@
+package raw
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/volatiletech/sqlboiler/v4/queries"
+)
+
+func rawQueryClosed(ctx context.Context, db *sql.DB) error {
+	rows, err := queries.Raw("SELECT id FROM users").QueryContext(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+
+	return rows.Err()
+}
+
+func rawQueryNotClosed(ctx context.Context, db *sql.DB) {
+	rows, err := queries.Raw("SELECT id FROM users WHERE age > $1", 18).QueryContext(ctx, db) // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func rawQueryWithoutContextNotClosed(db *sql.DB) {
+	rows, err := queries.Raw("SELECT id FROM users").Query(db) // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func rawQueryNotDeferred(ctx context.Context, db *sql.DB) {
+	rows, err := queries.Raw("SELECT id FROM users").QueryContext(ctx, db)
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+	rows.Close() // want "Close should use defer"
+}
+
+func usersByAge(ctx context.Context, db *sql.DB) []*User {
+	users, err := UsersByAge(ctx, db, 18)
+	if err != nil {
+		return nil
+	}
+
+	return users
+}
@@ -0,0 +1,20 @@
+package skipped
+
+import (
+	"context"
+	"database/sql"
+)
+
+func countUsers(ctx context.Context, exec *sql.DB) int {
+	rows, err := UsersRows(ctx, exec) // want "database/sql.Rows was not closed"
+	if err != nil {
+		return 0
+	}
+
+	n := 0
+	for rows.Next() {
+		n++
+	}
+
+	return n
+}
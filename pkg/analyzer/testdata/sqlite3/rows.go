@@ -0,0 +1,82 @@
+package sqlite3
+
+import (
+	"database/sql/driver"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+func correctDeferRows(stmt driver.Stmt) ([]string, error) {
+	rows, err := stmt.Query(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sqliteRows := rows.(*sqlite3.SQLiteRows)
+	defer sqliteRows.Close()
+
+	return sqliteRows.DeclTypes(), nil
+}
+
+func missingCloseRows(stmt driver.Stmt) ([]string, error) {
+	rows, err := stmt.Query(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sqliteRows := rows.(*sqlite3.SQLiteRows) // want "github.com/mattn/go-sqlite3.SQLiteRows was not closed"
+
+	return sqliteRows.DeclTypes(), nil
+}
+
+func commaOkDeferRows(stmt driver.Stmt) ([]string, bool) {
+	rows, err := stmt.Query(nil)
+	if err != nil {
+		return nil, false
+	}
+
+	sqliteRows, ok := rows.(*sqlite3.SQLiteRows)
+	if !ok {
+		return nil, false
+	}
+	defer sqliteRows.Close()
+
+	return sqliteRows.DeclTypes(), true
+}
+
+func commaOkMissingCloseRows(stmt driver.Stmt) ([]string, bool) {
+	rows, err := stmt.Query(nil)
+	if err != nil {
+		return nil, false
+	}
+
+	sqliteRows, ok := rows.(*sqlite3.SQLiteRows) // want "github.com/mattn/go-sqlite3.SQLiteRows was not closed"
+	if !ok {
+		return nil, false
+	}
+
+	return sqliteRows.DeclTypes(), true
+}
+
+func nonDeferStmt(conn *sqlite3.SQLiteConn) bool {
+	stmt, err := conn.Prepare("SELECT id FROM users")
+	if err != nil {
+		return false
+	}
+
+	sqliteStmt := stmt.(*sqlite3.SQLiteStmt)
+	readonly := sqliteStmt.Readonly()
+	sqliteStmt.Close() // want "Close should use defer"
+
+	return readonly
+}
+
+func missingCloseBackup(dest, src *sqlite3.SQLiteConn) error {
+	backup, err := dest.Backup("main", src, "main") // want "github.com/mattn/go-sqlite3.SQLiteBackup was not closed"
+	if err != nil {
+		return err
+	}
+
+	_, err = backup.Step(-1)
+	return err
+}
@@ -0,0 +1,158 @@
+package sqlx
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type user struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func deferredRowsClose(ctx context.Context, db *sqlx.DB) {
+	rows, err := db.QueryxContext(ctx, "SELECT id, name FROM users")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func rowsNotClosed(ctx context.Context, db *sqlx.DB) {
+	rows, err := db.QueryxContext(ctx, "SELECT id, name FROM users") // want "github.com/jmoiron/sqlx.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func deferredStmtClose(ctx context.Context, db *sqlx.DB) {
+	stmt, err := db.PreparexContext(ctx, "SELECT id, name FROM users WHERE id = ?")
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	var u user
+	_ = stmt.GetContext(ctx, &u, 1)
+}
+
+func stmtNotClosed(ctx context.Context, db *sqlx.DB) {
+	stmt, err := db.PreparexContext(ctx, "SELECT id, name FROM users WHERE id = ?") // want "github.com/jmoiron/sqlx.Stmt was not closed"
+	if err != nil {
+		return
+	}
+
+	var u user
+	_ = stmt.GetContext(ctx, &u, 1)
+}
+
+func deferredNamedStmtClose(ctx context.Context, db *sqlx.DB) {
+	stmt, err := db.PrepareNamedContext(ctx, "SELECT id, name FROM users WHERE id = :id")
+	if err != nil {
+		return
+	}
+	defer stmt.Close()
+
+	var u user
+	_ = stmt.GetContext(ctx, &u, user{ID: 1})
+}
+
+func namedStmtNotClosed(ctx context.Context, db *sqlx.DB) {
+	stmt, err := db.PrepareNamedContext(ctx, "SELECT id, name FROM users WHERE id = :id") // want "github.com/jmoiron/sqlx.NamedStmt was not closed"
+	if err != nil {
+		return
+	}
+
+	var u user
+	_ = stmt.GetContext(ctx, &u, user{ID: 1})
+}
+
+func namedStmtNotDeferred(ctx context.Context, db *sqlx.DB) {
+	stmt, err := db.PrepareNamedContext(ctx, "SELECT id, name FROM users WHERE id = :id")
+	if err != nil {
+		return
+	}
+
+	var u user
+	_ = stmt.GetContext(ctx, &u, user{ID: 1})
+	stmt.Close() // want "Close should use defer"
+}
+
+func txNamedStmtNotClosed(ctx context.Context, tx *sqlx.Tx) {
+	stmt, err := tx.PrepareNamedContext(ctx, "UPDATE users SET name = :name WHERE id = :id") // want "github.com/jmoiron/sqlx.NamedStmt was not closed"
+	if err != nil {
+		return
+	}
+
+	_, _ = stmt.ExecContext(ctx, user{ID: 1, Name: "alice"})
+}
+
+func payloadNamedQueryClosed(ctx context.Context, db *sqlx.DB) {
+	rows, err := db.NamedQueryContext(ctx, "SELECT id FROM users WHERE name = :name", user{Name: "alice"})
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func connNotClosed(ctx context.Context, db *sqlx.DB) {
+	conn, err := db.Connx(ctx) // want "github.com/jmoiron/sqlx.Conn was not closed"
+	if err != nil {
+		return
+	}
+
+	_ = conn.PingContext(ctx)
+}
+
+func deferredConnClose(ctx context.Context, db *sqlx.DB) {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	_ = conn.PingContext(ctx)
+}
+
+func beginxCommitted(db *sqlx.DB) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE users SET active = false"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func mustBeginCommitted(db *sqlx.DB) error {
+	tx := db.MustBegin()
+	defer tx.Rollback()
+
+	tx.MustExec("UPDATE users SET active = false")
+
+	return tx.Commit()
+}
+
+func beginxNotFinished(db *sqlx.DB) error {
+	tx, err := db.Beginx() // want "Tx was neither committed nor rolled back"
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE users SET active = false")
+	return err
+}
@@ -7,7 +7,7 @@ import (
 
 func missingClose() {
 	// In normal use, create one Stmt when your process starts.
-	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE id = ?") // want "Rows/Stmt/NamedStmt was not closed"
+	stmt, err := db.PrepareContext(ctx, "SELECT username FROM users WHERE id = ?") // want "database/sql.Stmt was not closed"
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,54 @@
+package stmt
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+func deferredOnceClose(ctx context.Context, db *sql.DB) {
+	stmt, err := db.PrepareContext(ctx, "UPDATE users SET active = false")
+	if err != nil {
+		return
+	}
+
+	var once sync.Once
+	defer once.Do(func() { stmt.Close() })
+
+	_, _ = stmt.ExecContext(ctx)
+}
+
+func closeOnceHelper(ctx context.Context, db *sql.DB) {
+	stmt, err := db.PrepareContext(ctx, "UPDATE users SET active = false")
+	if err != nil {
+		return
+	}
+
+	var once sync.Once
+	closeStmt := func() {
+		once.Do(func() {
+			_ = stmt.Close()
+		})
+	}
+	defer closeStmt()
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		closeStmt()
+		return
+	}
+}
+
+func onceNeverDone(ctx context.Context, db *sql.DB) {
+	stmt, err := db.PrepareContext(ctx, "UPDATE users SET active = false") // want "database/sql.Stmt was not closed"
+	if err != nil {
+		return
+	}
+
+	var once sync.Once
+	closeStmt := func() {
+		once.Do(func() { stmt.Close() })
+	}
+	_ = closeStmt
+
+	_, _ = stmt.ExecContext(ctx)
+}
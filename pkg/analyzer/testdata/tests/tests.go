@@ -0,0 +1,16 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+)
+
+func notClosed(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT name FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
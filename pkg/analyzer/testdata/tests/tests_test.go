@@ -0,0 +1,18 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestNotClosed(t *testing.T) {
+	var db *sql.DB
+	rows, err := db.QueryContext(context.Background(), "SELECT name FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for rows.Next() {
+	}
+}
@@ -0,0 +1,111 @@
+package txstmts
+
+import (
+	"context"
+	"database/sql"
+)
+
+func preparedOnRolledBackTx(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO users (username) VALUES (?)")
+	if err != nil {
+		return err
+	}
+
+	if _, err := stmt.ExecContext(ctx, "alice"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func preparedOnCommittedTx(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO users (username) VALUES (?)")
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err := stmt.ExecContext(ctx, "alice"); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func txStmtOfDBStmt(ctx context.Context, db *sql.DB, insert *sql.Stmt) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.StmtContext(ctx, insert).ExecContext(ctx, "alice"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func preparedOnUnfinishedTx(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil) // want "Tx was neither committed nor rolled back"
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO users (username) VALUES (?)") // want "database/sql.Stmt was not closed"
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, "alice")
+	return err
+}
+
+func preparedOnDB(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO users (username) VALUES (?)") // want "database/sql.Stmt was not closed"
+	if err != nil {
+		return err
+	}
+
+	if _, err := stmt.ExecContext(ctx, "alice"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func rowsQueriedOnCommittedTx(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM users WHERE active FOR UPDATE") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+	}
+
+	return tx.Commit()
+}
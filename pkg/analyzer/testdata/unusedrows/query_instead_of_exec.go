@@ -0,0 +1,45 @@
+package unusedrows
+
+import (
+	"context"
+	"database/sql"
+)
+
+func queryInsteadOfExec(ctx context.Context, db *sql.DB) error {
+	_, err := db.QueryContext(ctx, "INSERT INTO users (username) VALUES ('alice')") // want "Rows are never iterated nor closed, use Exec for statements that return no rows"
+	return err
+}
+
+func queryInsteadOfExecChecked(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "UPDATE users SET username = 'bob'") // want "Rows are never iterated nor closed, use Exec for statements that return no rows"
+	if err != nil {
+		return err
+	}
+
+	return rows.Err()
+}
+
+func iteratedNotClosed(ctx context.Context, db *sql.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT username FROM users") // want "database/sql.Rows was not closed"
+	if err != nil {
+		return
+	}
+
+	for rows.Next() {
+	}
+}
+
+func queryClosed(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "INSERT INTO users (username) VALUES ('alice')")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return nil
+}
+
+func execInstead(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "INSERT INTO users (username) VALUES ('alice')")
+	return err
+}
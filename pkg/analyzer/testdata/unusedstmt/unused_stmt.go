@@ -0,0 +1,67 @@
+package unusedstmt
+
+import (
+	"context"
+	"database/sql"
+)
+
+func preparedNeverUsed(ctx context.Context, db *sql.DB) error {
+	stmt, err := db.PrepareContext(ctx, "UPDATE users SET active = false") // want "database/sql.Stmt was not closed" "prepared Stmt is never used"
+	if err != nil {
+		return err
+	}
+
+	_ = stmt
+	return nil
+}
+
+func preparedClosedNeverUsed(ctx context.Context, db *sql.DB) error {
+	stmt, err := db.PrepareContext(ctx, "UPDATE users SET active = false") // want "prepared Stmt is never used"
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	return nil
+}
+
+func preparedAndExecuted(ctx context.Context, db *sql.DB) error {
+	stmt, err := db.PrepareContext(ctx, "UPDATE users SET active = false")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx)
+	return err
+}
+
+func preparedAndQueried(ctx context.Context, db *sql.DB) error {
+	stmt, err := db.PrepareContext(ctx, "SELECT 1")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	var one int
+	return stmt.QueryRowContext(ctx).Scan(&one)
+}
+
+func execute(ctx context.Context, stmt *sql.Stmt) error {
+	_, err := stmt.ExecContext(ctx)
+	return err
+}
+
+func preparedAndPassed(ctx context.Context, db *sql.DB) error {
+	stmt, err := db.PrepareContext(ctx, "UPDATE users SET active = false")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	return execute(ctx, stmt)
+}
+
+func preparedForCaller(ctx context.Context, db *sql.DB) (*sql.Stmt, error) {
+	return db.PrepareContext(ctx, "UPDATE users SET active = false")
+}
@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Result wraps the rows of a query and must be closed instead of them.
+type Result struct {
+	rows *sql.Rows
+}
+
+func Query(ctx context.Context, db *sql.DB, query string) (*Result, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{rows: rows}, nil
+}
+
+func (r *Result) Next() bool {
+	return r.rows.Next()
+}
+
+func (r *Result) Close() error {
+	return r.rows.Close()
+}
@@ -0,0 +1,41 @@
+package wrapperresult
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer/testdata/wrapperresult/db"
+)
+
+func resultClosed(ctx context.Context, conn *sql.DB) {
+	result, err := db.Query(ctx, conn, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+	defer result.Close()
+
+	for result.Next() {
+	}
+}
+
+func resultNotClosed(ctx context.Context, conn *sql.DB) {
+	result, err := db.Query(ctx, conn, "SELECT username FROM users") // want "db.Result was not closed"
+	if err != nil {
+		return
+	}
+
+	for result.Next() {
+	}
+}
+
+func resultNotDeferred(ctx context.Context, conn *sql.DB) {
+	result, err := db.Query(ctx, conn, "SELECT username FROM users")
+	if err != nil {
+		return
+	}
+
+	for result.Next() {
+	}
+
+	result.Close() // want "Close should use defer"
+}
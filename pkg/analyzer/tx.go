@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// txName is the transaction type, it is committed or rolled back instead of closed
+const txName = "Tx"
+
+// txRollbackUnlessCommitted ends a transaction of some Tx types only, e.g. the
+// deferred tx.RollbackUnlessCommitted() of dbr, so isTxType doesn't require it
+const txRollbackUnlessCommitted = "RollbackUnlessCommitted"
+
+// getTxTypeFromName returns the transaction type of pkg, a pointer to it
+// unless it is an interface, e.g. pgx.Tx
+func getTxTypeFromName(pkg *types.Package) types.Type {
+	obj, ok := pkg.Scope().Lookup(txName).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	var t types.Type = types.NewPointer(named)
+	if types.IsInterface(named) {
+		t = named
+	}
+
+	if !isTxType(t) {
+		// a type that shares the name but can't be committed nor rolled back
+		return nil
+	}
+
+	return t
+}
+
+// isTxType reports whether t is a Tx type with the methods ending the
+// transaction, e.g. *sql.Tx, *sqlx.Tx embedding it or the pgx.Tx interface
+func isTxType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if ptr, isPtr := t.(*types.Pointer); isPtr {
+		named, ok = ptr.Elem().(*types.Named)
+	} else if ok && !types.IsInterface(named) {
+		return false
+	}
+
+	if !ok || named.Obj().Name() != txName {
+		return false
+	}
+
+	methods := types.NewMethodSet(t)
+	for _, method := range txEnds {
+		if methods.Lookup(nil, method) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isTxEnd reports whether name is one of the methods ending the transaction
+func isTxEnd(name string) bool {
+	if name == txRollbackUnlessCommitted {
+		return true
+	}
+
+	for _, method := range txEnds {
+		if name == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// endsTx reports whether call commits or rolls back a transaction
+func endsTx(call *ssa.CallCommon) bool {
+	if call.IsInvoke() {
+		return isTxEnd(call.Method.Name()) && isTxType(call.Value.Type())
+	}
+
+	callee := call.StaticCallee()
+	if callee == nil || callee.Signature.Recv() == nil {
+		return false
+	}
+
+	return isTxEnd(callee.Name()) && isTxType(callee.Signature.Recv().Type())
+}
@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// txEnds are the methods of a transaction releasing the statements prepared on it
+var txEnds = []string{"Rollback", "Commit"}
+
+// txReleased reports whether the statement created by the call tv was prepared
+// on a transaction that is rolled back or committed, which closes the
+// statement as well, e.g. stmt, _ := tx.Prepare(query) and defer tx.Rollback()
+func txReleased(tv targetValue) bool {
+	call, ok := tv.instr.(*ssa.Call)
+	if !ok || !isTxPrepare(call.Call.StaticCallee()) || len(call.Call.Args) == 0 {
+		return false
+	}
+
+	tx := call.Call.Args[0]
+	for _, ref := range *tx.Referrers() {
+		for _, method := range txEnds {
+			if _, ok := methodCall(ref, tx, method); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isTxPrepare reports whether fn is a method of a Tx type preparing a
+// statement, e.g. (*sql.Tx).PrepareContext, (*sql.Tx).Stmt or (*sqlx.Tx).Preparex
+func isTxPrepare(fn *ssa.Function) bool {
+	if fn == nil || fn.Signature.Recv() == nil {
+		return false
+	}
+
+	recv := fn.Signature.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+
+	named, ok := recv.(*types.Named)
+	if !ok || named.Obj().Name() != "Tx" {
+		return false
+	}
+
+	return strings.HasPrefix(fn.Name(), "Prepare") || strings.HasPrefix(fn.Name(), "Stmt")
+}
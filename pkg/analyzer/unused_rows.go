@@ -0,0 +1,26 @@
+package analyzer
+
+import (
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+const nextMethod = "Next"
+
+// isUnusedRows reports whether v is a Rows that is never iterated, which
+// usually means a statement returning no rows was run with Query instead of
+// Exec
+func isUnusedRows(v ssa.Value) bool {
+	if !strings.HasSuffix(targetTypeName(v.Type()), ":"+rowsName) {
+		return false
+	}
+
+	for _, ref := range *v.Referrers() {
+		if _, ok := methodCall(ref, v, nextMethod); ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// execPrefixes are the prefixes of the methods running a prepared statement,
+// e.g. ExecContext, QueryRow or sqlx's Get and Select
+var execPrefixes = []string{"Exec", "Query", "MustExec", "Get", "Select"}
+
+// isUnusedStmt reports whether v is a Stmt, or NamedStmt, on which no method
+// running it is called. Closing it doesn't use it, while handing it over, e.g.
+// by passing or returning it, is trusted to.
+func isUnusedStmt(v ssa.Value) bool {
+	name := targetTypeName(v.Type())
+	if !strings.HasSuffix(name, ":"+stmtName) && !strings.HasSuffix(name, ":"+namedStmtName) {
+		return false
+	}
+
+	for _, ref := range *v.Referrers() {
+		method, ok := calledMethod(ref, v)
+		if !ok {
+			return false
+		}
+
+		for _, prefix := range execPrefixes {
+			if strings.HasPrefix(method, prefix) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// calledMethod returns the name of the method on v called or deferred by instr
+func calledMethod(instr ssa.Instruction, v ssa.Value) (string, bool) {
+	call, ok := callCommon(instr)
+	if !ok {
+		return "", false
+	}
+
+	if call.IsInvoke() {
+		return call.Method.Name(), call.Value == v
+	}
+
+	callee := call.StaticCallee()
+	if callee == nil || callee.Signature.Recv() == nil || len(call.Args) == 0 || call.Args[0] != v {
+		return "", false
+	}
+
+	return callee.Name(), true
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+type objectFactKey struct {
+	obj types.Object
+	typ reflect.Type
+}
+
+type packageFactKey struct {
+	pkg *types.Package
+	typ reflect.Type
+}
+
+// facts holds the facts exported by the packages analyzed so far, for the
+// packages importing them
+type facts struct {
+	objects  map[objectFactKey]analysis.Fact
+	packages map[packageFactKey]analysis.Fact
+}
+
+func newFacts() *facts {
+	return &facts{
+		objects:  map[objectFactKey]analysis.Fact{},
+		packages: map[packageFactKey]analysis.Fact{},
+	}
+}
+
+// bind sets the fact functions of pass to import and export the facts of s
+func (s *facts) bind(pass *analysis.Pass) {
+	pass.ImportObjectFact = func(obj types.Object, fact analysis.Fact) bool {
+		stored, ok := s.objects[objectFactKey{obj, reflect.TypeOf(fact)}]
+		if ok {
+			reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+		}
+		return ok
+	}
+	pass.ExportObjectFact = func(obj types.Object, fact analysis.Fact) {
+		s.objects[objectFactKey{obj, reflect.TypeOf(fact)}] = fact
+	}
+	pass.ImportPackageFact = func(pkg *types.Package, fact analysis.Fact) bool {
+		stored, ok := s.packages[packageFactKey{pkg, reflect.TypeOf(fact)}]
+		if ok {
+			reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+		}
+		return ok
+	}
+	pass.ExportPackageFact = func(fact analysis.Fact) {
+		s.packages[packageFactKey{pass.Pkg, reflect.TypeOf(fact)}] = fact
+	}
+	pass.AllObjectFacts = func() []analysis.ObjectFact {
+		all := make([]analysis.ObjectFact, 0, len(s.objects))
+		for key, fact := range s.objects {
+			all = append(all, analysis.ObjectFact{Object: key.obj, Fact: fact})
+		}
+		return all
+	}
+	pass.AllPackageFacts = func() []analysis.PackageFact {
+		all := make([]analysis.PackageFact, 0, len(s.packages))
+		for key, fact := range s.packages {
+			all = append(all, analysis.PackageFact{Package: key.pkg, Fact: fact})
+		}
+		return all
+	}
+}
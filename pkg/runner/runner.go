@@ -0,0 +1,407 @@
+// Package runner runs an analyzer as a standalone command. Unlike
+// singlechecker it owns the output, so findings can be emitted as JSON with a
+// stable fingerprint. Invocations by go vet are handed to singlechecker.
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/singlechecker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+)
+
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitFindings = 3
+)
+
+// Finding is a diagnostic reported by the analyzer
+type Finding struct {
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Column      int    `json:"column"`
+	Category    string `json:"category,omitempty"`
+	Check       string `json:"check,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Message     string `json:"message"`
+	Severity    string `json:"severity"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Main runs the analyzer with the command line arguments and exits.
+func Main(a *analysis.Analyzer) {
+	args := os.Args[1:]
+	if isVetInvocation(args) {
+		singlechecker.Main(a)
+		return
+	}
+
+	os.Exit(Run(a, args, os.Stdout, os.Stderr))
+}
+
+// isVetInvocation reports whether the command is run with -vettool
+func isVetInvocation(args []string) bool {
+	for _, arg := range args {
+		if arg == "-flags" || strings.HasPrefix(arg, "-V") {
+			return true
+		}
+	}
+
+	return len(args) > 0 && strings.HasSuffix(args[len(args)-1], ".cfg")
+}
+
+// Run analyzes the packages matching the patterns in args and writes the
+// findings to stdout as JSON, or to stderr as text. It returns the exit code,
+// findings only fail the run when more than -max-findings of them have error
+// severity.
+func Run(a *analysis.Analyzer, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(a.Name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	a.Flags.VisitAll(func(f *flag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+	})
+	jsonOutput := flags.Bool("json", false, "emit findings as a JSON array")
+	statsJSON := flags.String("stats-json", "", "write analysis statistics as JSON to this file, - for stdout")
+	maxFindings := flags.Int("max-findings", 0, "only fail when more than this many error findings are reported")
+	emitSuppressions := flags.Bool("emit-suppressions", false,
+		"instead of failing, write the //nolint:sqlclosecheck comments suppressing the findings as file:line: comment")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "%s: %s\n\nUsage: %s [-flag] [package]\n\nFlags:\n", a.Name, a.Doc, a.Name)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if f := flags.Lookup("list-checks"); f != nil && f.Value.String() == "true" {
+		if err := analyzer.WriteChecks(stdout); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitError
+	}
+
+	// The stats are kept apart from the findings written to stdout
+	if *statsJSON == "-" && (*jsonOutput || *emitSuppressions) {
+		fmt.Fprintf(stderr, "%s: -stats-json=- can't be combined with -json or -emit-suppressions, write the stats to a file\n", a.Name)
+		return exitError
+	}
+
+	start := time.Now()
+	findings, stats, err := analyze(a, flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+		return exitError
+	}
+	stats.ElapsedMS = time.Since(start).Milliseconds()
+
+	if *emitSuppressions {
+		if err := writeSuppressions(stdout, findings); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			return exitError
+		}
+	} else if *jsonOutput {
+		if err := writeJSON(stdout, findings); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			return exitError
+		}
+	} else {
+		color := isTerminal(stderr)
+		for _, f := range findings {
+			fmt.Fprintf(stderr, "%s:%d:%d: %s%s\n", f.File, f.Line, f.Column, severityPrefix(f.Severity, color), f.Message)
+		}
+	}
+
+	if *statsJSON != "" {
+		if err := writeStats(*statsJSON, stats, stdout); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", a.Name, err)
+			return exitError
+		}
+	}
+
+	// The suppressions are the output, the findings don't fail the run
+	if *emitSuppressions {
+		return exitOK
+	}
+
+	errors := 0
+	for _, f := range findings {
+		if f.Severity == string(analyzer.SeverityError) {
+			errors++
+		}
+	}
+
+	if errors > *maxFindings {
+		return exitFindings
+	}
+
+	return exitOK
+}
+
+// RunJSON analyzes the packages matching the patterns with a as configured,
+// e.g. by NewAnalyzerWithOptions, and writes the findings to w as a JSON array.
+// The findings are returned too, for the caller to decide whether they fail.
+func RunJSON(a *analysis.Analyzer, patterns []string, w io.Writer) ([]Finding, error) {
+	findings, _, err := analyze(a, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeJSON(w, findings); err != nil {
+		return nil, err
+	}
+
+	return findings, nil
+}
+
+func writeJSON(w io.Writer, findings []Finding) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
+
+var severityColors = map[string]string{
+	string(analyzer.SeverityError):   "\x1b[31m",
+	string(analyzer.SeverityWarning): "\x1b[33m",
+	string(analyzer.SeverityInfo):    "\x1b[36m",
+}
+
+// severityPrefix labels findings that are not errors, so the default output
+// stays the same as go vet's
+func severityPrefix(severity string, color bool) string {
+	if severity == string(analyzer.SeverityError) && !color {
+		return ""
+	}
+
+	if color {
+		return severityColors[severity] + severity + ":\x1b[0m "
+	}
+
+	return severity + ": "
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func analyze(a *analysis.Analyzer, patterns []string) ([]Finding, *Stats, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports |
+			packages.NeedTypes | packages.NeedTypesSizes | packages.NeedSyntax | packages.NeedTypesInfo |
+			packages.NeedModule,
+		Tests: true,
+	}
+	if len(a.FactTypes) > 0 {
+		// The dependencies are analyzed first, for their facts
+		cfg.Mode |= packages.NeedDeps
+	}
+
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, nil, fmt.Errorf("%d errors loading packages", n)
+	}
+
+	roots := map[*packages.Package]bool{}
+	for _, pkg := range pkgs {
+		roots[pkg] = true
+	}
+
+	// Visit the dependencies before the packages importing them
+	var order []*packages.Package
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if roots[pkg] || len(a.FactTypes) > 0 {
+			order = append(order, pkg)
+		}
+	})
+
+	stats := newStats()
+
+	findings := []Finding{}
+	seen := map[string]bool{}
+	exported := newFacts()
+	for _, pkg := range order {
+		results := map[*analysis.Analyzer]interface{}{}
+		diags, err := runPass(a, pkg, results, exported)
+		if err != nil {
+			return nil, nil, fmt.Errorf("analyzing %s: %w", pkg.ID, err)
+		}
+
+		// Only the facts of the dependencies are of use
+		if !roots[pkg] {
+			continue
+		}
+
+		result, _ := results[a].(*analyzer.Result)
+		stats.addPackage(result)
+		for _, d := range diags {
+			f := newFinding(pkg, d)
+			f.Severity = string(analyzer.SeverityError)
+			if result != nil {
+				if rd, ok := result.Lookup(d.Pos, d.Message); ok {
+					f.Severity = string(rd.Severity)
+					f.Check = rd.Check
+					f.Type = rd.Type
+				}
+			}
+			// The package and its test variant share files
+			key := fmt.Sprintf("%s:%d:%d: %s", f.File, f.Line, f.Column, f.Message)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, f)
+			stats.addFinding(f)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+
+	return findings, stats, nil
+}
+
+// runPass runs a and the analyzers it requires on pkg, results are memoized in
+// results. Facts are imported from and exported to exported.
+func runPass(a *analysis.Analyzer, pkg *packages.Package, results map[*analysis.Analyzer]interface{},
+	exported *facts,
+) ([]analysis.Diagnostic, error) {
+	resultOf := map[*analysis.Analyzer]interface{}{}
+	for _, req := range a.Requires {
+		if _, ok := results[req]; !ok {
+			if _, err := runPass(req, pkg, results, exported); err != nil {
+				return nil, err
+			}
+		}
+		resultOf[req] = results[req]
+	}
+
+	diags := []analysis.Diagnostic{}
+	pass := &analysis.Pass{
+		Analyzer:     a,
+		Fset:         pkg.Fset,
+		Files:        pkg.Syntax,
+		OtherFiles:   pkg.OtherFiles,
+		IgnoredFiles: pkg.IgnoredFiles,
+		Pkg:          pkg.Types,
+		TypesInfo:    pkg.TypesInfo,
+		TypesSizes:   pkg.TypesSizes,
+		ResultOf:     resultOf,
+		Report:       func(d analysis.Diagnostic) { diags = append(diags, d) },
+	}
+	exported.bind(pass)
+
+	result, err := a.Run(pass)
+	if err != nil {
+		return nil, err
+	}
+	results[a] = result
+
+	return diags, nil
+}
+
+func newFinding(pkg *packages.Package, d analysis.Diagnostic) Finding {
+	posn := pkg.Fset.Position(d.Pos)
+	return Finding{
+		File:        posn.Filename,
+		Line:        posn.Line,
+		Column:      posn.Column,
+		Category:    d.Category,
+		Message:     d.Message,
+		Fingerprint: fingerprint(pkg, d),
+	}
+}
+
+// fingerprint identifies a finding across commits. It hashes the package path,
+// the enclosing function and the position relative to that function, so edits
+// elsewhere in the file that shift lines keep the fingerprint stable.
+func fingerprint(pkg *packages.Package, d analysis.Diagnostic) string {
+	posn := pkg.Fset.Position(d.Pos)
+	funcName, line := "", posn.Line
+	if decl := enclosingFunc(pkg, d.Pos); decl != nil {
+		funcName = funcDeclName(decl)
+		line -= pkg.Fset.Position(decl.Pos()).Line
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d:%d\x00%s", pkg.PkgPath, funcName, line, posn.Column, d.Message)
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+func enclosingFunc(pkg *packages.Package, pos token.Pos) *ast.FuncDecl {
+	for _, f := range pkg.Syntax {
+		if pos < f.Pos() || pos > f.End() {
+			continue
+		}
+
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Pos() <= pos && pos <= fn.End() {
+				return fn
+			}
+		}
+	}
+
+	return nil
+}
+
+func funcDeclName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+
+	recv := decl.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+
+	// Drop type parameters of generic receivers
+	switch r := recv.(type) {
+	case *ast.IndexExpr:
+		recv = r.X
+	case *ast.IndexListExpr:
+		recv = r.X
+	}
+
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + decl.Name.Name
+	}
+
+	return decl.Name.Name
+}
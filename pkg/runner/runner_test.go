@@ -0,0 +1,289 @@
+package runner_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+	"github.com/ryanrolds/sqlclosecheck/pkg/runner"
+)
+
+const leakSrc = `package leak
+
+import (
+	"context"
+	"database/sql"
+)
+
+func leak(ctx context.Context, db *sql.DB) {
+	rows, _ := db.QueryContext(ctx, "SELECT 1")
+	_ = rows
+}
+`
+
+const shiftedLeakSrc = `package leak
+
+import (
+	"context"
+	"database/sql"
+)
+
+// unrelated is added above and shifts the leak down
+func unrelated() int {
+	return 42
+}
+
+func leak(ctx context.Context, db *sql.DB) {
+	rows, _ := db.QueryContext(ctx, "SELECT 1")
+	_ = rows
+}
+`
+
+const leakTwiceSrc = `
+func leakAgain(ctx context.Context, db *sql.DB) {
+	rows, _ := db.QueryContext(ctx, "SELECT 2")
+	_ = rows
+}
+`
+
+// run analyzes a module holding src with args and returns the exit code and output
+func run(t *testing.T, src string, args ...string) (int, string, string) {
+	t.Helper()
+
+	defer chdirModule(t, src)()
+
+	var stdout, stderr bytes.Buffer
+	code := runner.Run(analyzer.NewAnalyzer(), append(args, "./..."), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// chdirModule changes to a module holding src and returns the func changing back
+func chdirModule(t *testing.T, src string) func() {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/leak\n\ngo 1.20\n")
+	writeFile(t, filepath.Join(dir, "leak.go"), src)
+
+	return chdir(t, dir)
+}
+
+// chdir changes to dir and returns the func changing back
+func chdir(t *testing.T, dir string) func() {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// runJSON analyzes a module holding src and returns the JSON findings
+func runJSON(t *testing.T, src string) []runner.Finding {
+	t.Helper()
+
+	code, stdout, stderr := run(t, src, "-json")
+	if code != 3 {
+		t.Fatalf("expected exit code 3, got %d: %s", code, stderr)
+	}
+
+	var findings []runner.Finding
+	if err := json.Unmarshal([]byte(stdout), &findings); err != nil {
+		t.Fatalf("decoding %q: %v", stdout, err)
+	}
+
+	return findings
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFingerprintStableUnderUnrelatedEdits(t *testing.T) {
+	before := runJSON(t, leakSrc)
+	after := runJSON(t, shiftedLeakSrc)
+
+	if len(before) != 1 || len(after) != 1 {
+		t.Fatalf("expected one finding each, got %v and %v", before, after)
+	}
+
+	if before[0].Line == after[0].Line {
+		t.Fatalf("expected the edit to shift the finding, both are on line %d", before[0].Line)
+	}
+
+	if before[0].Fingerprint == "" || before[0].Fingerprint != after[0].Fingerprint {
+		t.Errorf("fingerprint changed from %q to %q", before[0].Fingerprint, after[0].Fingerprint)
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	findings := runJSON(t, leakSrc)
+	if len(findings) != 1 || findings[0].Severity != "error" {
+		t.Fatalf("expected one finding with error severity, got %v", findings)
+	}
+
+	code, _, stderr := run(t, leakSrc, "-severity", "database/sql:Rows:warning")
+	if code != 0 {
+		t.Errorf("expected exit code 0 for warnings, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, ": warning: database/sql.Rows was not closed") {
+		t.Errorf("expected a warning prefix, got %q", stderr)
+	}
+
+	code, _, stderr = run(t, leakSrc, "-severity", "database/sql:Stmt:info")
+	if code != 3 {
+		t.Errorf("expected exit code 3 for errors, got %d: %s", code, stderr)
+	}
+	if strings.Contains(stderr, "error:") {
+		t.Errorf("expected no prefix for errors, got %q", stderr)
+	}
+}
+
+func TestMaxFindings(t *testing.T) {
+	if code, _, stderr := run(t, leakSrc, "-max-findings", "1"); code != 0 {
+		t.Errorf("expected exit code 0 at the threshold, got %d: %s", code, stderr)
+	}
+
+	code, _, stderr := run(t, shiftedLeakSrc+leakTwiceSrc, "-max-findings", "1")
+	if code != 3 {
+		t.Errorf("expected exit code 3 above the threshold, got %d: %s", code, stderr)
+	}
+	if n := strings.Count(stderr, "was not closed"); n != 2 {
+		t.Errorf("expected all 2 findings to be reported, got %d: %s", n, stderr)
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	statsFile := filepath.Join(t.TempDir(), "stats.json")
+	code, stdout, stderr := run(t, shiftedLeakSrc+leakTwiceSrc, "-stats-json", statsFile)
+	if code != 3 {
+		t.Fatalf("expected exit code 3, got %d: %s", code, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("expected the stats to be kept out of stdout, got %q", stdout)
+	}
+
+	content, err := os.ReadFile(statsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stats runner.Stats
+	if err := json.Unmarshal(content, &stats); err != nil {
+		t.Fatalf("decoding %q: %v", content, err)
+	}
+
+	if stats.Packages != 1 || stats.Functions != 3 || stats.Targets == 0 || stats.MaxDepth == 0 {
+		t.Errorf("unexpected counts in %s", content)
+	}
+	if stats.Findings["unclosed"] != 2 {
+		t.Errorf("expected 2 unclosed findings, got %v", stats.Findings)
+	}
+	if stats.Categories[analyzer.CategoryLeak] != 2 {
+		t.Errorf("expected 2 leak findings, got %v", stats.Categories)
+	}
+
+	code, stdout, stderr = run(t, leakSrc, "-json", "-stats-json", "-")
+	if code != 1 || stdout != "" {
+		t.Errorf("expected -stats-json=- to be rejected with -json, got exit code %d and %q: %s", code, stdout, stderr)
+	}
+}
+
+func TestEmitSuppressions(t *testing.T) {
+	code, stdout, stderr := run(t, shiftedLeakSrc+leakTwiceSrc, "-emit-suppressions")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+	if stderr != "" {
+		t.Errorf("expected no findings on stderr, got %q", stderr)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a suppression per finding, got %q", stdout)
+	}
+
+	for i, line := range []int{14, 19} {
+		want := fmt.Sprintf("leak.go:%d: //nolint:sqlclosecheck // database/sql.Rows was not closed", line)
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("expected %q, got %q", want, lines[i])
+		}
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	defer chdirModule(t, leakSrc)()
+
+	var out bytes.Buffer
+	findings, err := runner.RunJSON(analyzer.NewAnalyzer(), []string{"./..."}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var written []runner.Finding
+	if err := json.Unmarshal(out.Bytes(), &written); err != nil {
+		t.Fatalf("decoding %q: %v", out.String(), err)
+	}
+
+	if len(written) != 1 || len(findings) != 1 || written[0] != findings[0] {
+		t.Fatalf("expected the returned finding to be written, got %v and %v", findings, written)
+	}
+
+	f := written[0]
+	if filepath.Base(f.File) != "leak.go" || f.Line != 9 || f.Column != 28 {
+		t.Errorf("unexpected position %s:%d:%d", f.File, f.Line, f.Column)
+	}
+	if f.Type != "database/sql.Rows" || f.Check != "unclosed" || f.Category != analyzer.CategoryLeak ||
+		f.Message != "database/sql.Rows was not closed" {
+		t.Errorf("unexpected finding %+v", f)
+	}
+}
+
+func TestFactsOfDependencies(t *testing.T) {
+	defer chdir(t, "../analyzer/testdata")()
+
+	var stdout, stderr bytes.Buffer
+	code := runner.Run(analyzer.NewAnalyzer(), []string{"-json", "./crosspkg"}, &stdout, &stderr)
+	if code != 3 {
+		t.Fatalf("expected exit code 3, got %d: %s", code, stderr.String())
+	}
+
+	var findings []runner.Finding
+	if err := json.Unmarshal(stdout.Bytes(), &findings); err != nil {
+		t.Fatalf("decoding %q: %v", stdout.String(), err)
+	}
+
+	// The Rows drained by dbutil.Drain are closed by it
+	if len(findings) != 1 || filepath.Base(findings[0].File) != "crosspkg.go" || findings[0].Line != 27 {
+		t.Errorf("expected only the leak in notDrained, got %v", findings)
+	}
+}
+
+func TestListChecks(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runner.Run(analyzer.NewAnalyzer(), []string{"-list-checks"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "CHECK") || !strings.Contains(stdout.String(), "unclosed") {
+		t.Errorf("expected the checks, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/ryanrolds/sqlclosecheck/pkg/analyzer"
+)
+
+// Stats summarizes a run for dashboards tracking the findings over time
+type Stats struct {
+	Packages  int `json:"packages"`
+	Functions int `json:"functions"`
+	Targets   int `json:"targets"`
+	// Findings counts the findings by check
+	Findings map[string]int `json:"findings"`
+	// Categories counts the findings by category, analyzer.CategoryLeak or analyzer.CategoryStyle
+	Categories map[string]int `json:"categories"`
+	MaxDepth   int            `json:"max_depth"`
+	ElapsedMS  int64          `json:"elapsed_ms"`
+}
+
+func newStats() *Stats {
+	return &Stats{Findings: map[string]int{}, Categories: map[string]int{}}
+}
+
+// addPackage accumulates the stats of the result of a package
+func (s *Stats) addPackage(result *analyzer.Result) {
+	s.Packages++
+	if result == nil {
+		return
+	}
+
+	s.Functions += result.Stats.Functions
+	s.Targets += result.Stats.Targets
+	if result.Stats.MaxDepth > s.MaxDepth {
+		s.MaxDepth = result.Stats.MaxDepth
+	}
+}
+
+func (s *Stats) addFinding(f Finding) {
+	check := f.Check
+	if check == "" {
+		check = "unknown"
+	}
+	s.Findings[check]++
+
+	category := f.Category
+	if category == "" {
+		category = "unknown"
+	}
+	s.Categories[category]++
+}
+
+// writeStats writes the stats as JSON to the file at path, or to stdout for -
+func writeStats(path string, stats *Stats, stdout io.Writer) (err error) {
+	w := stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(stats)
+}
@@ -0,0 +1,42 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// nolintDirective is the comment suppressing the findings of a line in golangci-lint
+const nolintDirective = "//nolint:sqlclosecheck"
+
+// writeSuppressions writes, per file and line, the nolint comment to append to
+// the line to suppress its findings, as file:line: comment. The messages of
+// the findings on the line are kept as the explanation of the comment. The
+// findings must be sorted by file and line.
+func writeSuppressions(w io.Writer, findings []Finding) error {
+	for i := 0; i < len(findings); {
+		f := findings[i]
+		messages := []string{}
+		for ; i < len(findings) && findings[i].File == f.File && findings[i].Line == f.Line; i++ {
+			if !contains(messages, findings[i].Message) {
+				messages = append(messages, findings[i].Message)
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%s:%d: %s // %s\n", f.File, f.Line, nolintDirective, strings.Join(messages, "; ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,40 @@
+package sqlx_examples
+
+import (
+	"log"
+)
+
+func missingCloseNamedStmtRows() {
+	stmt, err := db.PrepareNamed("SELECT * FROM users WHERE id = :id")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Queryx(map[string]interface{}{"id": 1})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// defer rows.Close()
+
+	for rows.Next() {
+	}
+}
+
+func correctNamedStmtRows() {
+	stmt, err := db.PrepareNamed("SELECT * FROM users WHERE id = :id")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.Queryx(map[string]interface{}{"id": 1})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+}